@@ -0,0 +1,199 @@
+package quadkey
+
+import (
+	"encoding/csv"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// --------------------------
+// struct Aggregator
+// --------------------------
+
+// Aggregator bins points into the tiles of one zoom and counts them, the
+// building block of tile heatmaps. Memory grows with the number of distinct
+// tiles hit, not with the number of points.
+//
+// An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	zoom   int
+	counts map[QuadKey]int64
+}
+
+// NewAggregator returns an empty aggregator binning at zoom, clamped to
+// [1, MaxZoom].
+func NewAggregator(zoom int) *Aggregator {
+	return &Aggregator{
+		zoom:   max(1, min(zoom, MaxZoom)),
+		counts: make(map[QuadKey]int64),
+	}
+}
+
+// Zoom returns the zoom points are binned at.
+func (a *Aggregator) Zoom() int {
+	return a.zoom
+}
+
+// Add counts one point in the tile holding it.
+func (a *Aggregator) Add(p orb.Point) {
+	a.counts[FromPoint(p, a.zoom)]++
+}
+
+// AddLonLat is Add for a lon/lat pair.
+func (a *Aggregator) AddLonLat(lon, lat float64) {
+	a.counts[FromLonLat(lon, lat, a.zoom)]++
+}
+
+// AddSeq counts every point of seq, so points can be streamed from a file or
+// query without being held in memory.
+func (a *Aggregator) AddSeq(seq iter.Seq[orb.Point]) {
+	for p := range seq {
+		a.Add(p)
+	}
+}
+
+// Count returns the number of points counted in the key's tile.
+func (a *Aggregator) Count(key QuadKey) int64 {
+	return a.counts[key]
+}
+
+// Len returns the number of tiles holding at least one point.
+func (a *Aggregator) Len() int {
+	return len(a.counts)
+}
+
+// Counts returns a copy of the per-tile counts.
+func (a *Aggregator) Counts() map[QuadKey]int64 {
+	return maps.Clone(a.counts)
+}
+
+// Keys returns the tiles holding at least one point, in quadkey order.
+func (a *Aggregator) Keys() []QuadKey {
+	return slices.Sorted(maps.Keys(a.counts))
+}
+
+// ToFeatureCollection returns one tile polygon per non-empty tile, in
+// quadkey order, with the key string as ID and the point count as a "count"
+// property, ready to style as a heatmap.
+func (a *Aggregator) ToFeatureCollection() *geojson.FeatureCollection {
+	return ToFeatureCollectionWithOptions(FeatureOptions{
+		Properties: func(key QuadKey) geojson.Properties {
+			return geojson.Properties{"count": a.counts[key]}
+		},
+	}, a.Keys()...)
+}
+
+// --------------------------
+// struct Stats
+// --------------------------
+
+// Stats accumulates a value of type T per tile, combining the values added
+// to one key with a merge function: sums, minimums and maximums, or custom
+// sketches. Unlike Aggregator, a Stats is safe for concurrent use.
+type Stats[T any] struct {
+	mu     sync.Mutex
+	merge  func(acc, v T) T
+	values map[QuadKey]T
+}
+
+// NewStats returns an empty accumulator. merge combines the value held for a
+// key with a newly added one; the first value added to a key is held as is.
+func NewStats[T any](merge func(acc, v T) T) *Stats[T] {
+	return &Stats[T]{merge: merge, values: make(map[QuadKey]T)}
+}
+
+// Add merges v into the key's value. Invalid keys are ignored.
+func (s *Stats[T]) Add(key QuadKey, v T) {
+	if key.Valid() != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.values[key]; ok {
+		v = s.merge(acc, v)
+	}
+	s.values[key] = v
+}
+
+// Get returns the key's value and whether any was added.
+func (s *Stats[T]) Get(key QuadKey) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Len returns the number of keys holding a value.
+func (s *Stats[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.values)
+}
+
+// Values returns a copy of the per-key values.
+func (s *Stats[T]) Values() map[QuadKey]T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.values)
+}
+
+// ToFeatureCollection returns one tile polygon per key, in quadkey order,
+// with the key string as ID and the properties props derives from its value.
+func (s *Stats[T]) ToFeatureCollection(props func(v T) geojson.Properties) *geojson.FeatureCollection {
+	values := s.Values()
+	return ToFeatureCollectionWithOptions(FeatureOptions{
+		Properties: func(key QuadKey) geojson.Properties { return props(values[key]) },
+	}, slices.Sorted(maps.Keys(values))...)
+}
+
+// WriteCSV writes the keys to w in quadkey order as WriteCSV does, with the
+// given extra columns appended to each row from row, which returns one field
+// per column for a value.
+func (s *Stats[T]) WriteCSV(w io.Writer, columns []string, row func(v T) []string) error {
+	values := s.Values()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(slices.Clone(csvHeader), columns...)); err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if err := cw.Write(append(csvRecord(key), row(values[key])...)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// --------------------------
+// rollups
+// --------------------------
+
+// Rollup aggregates per-key values into their ancestors at toZoom, combining
+// the values that land on one ancestor with reduce, or summing them if reduce
+// is nil. Keys may mix zooms; a key already at toZoom contributes its own
+// value, while invalid keys and keys coarser than toZoom are skipped. The
+// ancestor of a key is its first toZoom digits, so no Parent chains are
+// walked or keys allocated.
+func Rollup(values map[QuadKey]float64, toZoom int, reduce func(a, b float64) float64) map[QuadKey]float64 {
+	if reduce == nil {
+		reduce = func(a, b float64) float64 { return a + b }
+	}
+	out := make(map[QuadKey]float64)
+	for key, v := range values {
+		if key.Z() < toZoom || toZoom < 1 || key.Valid() != nil {
+			continue
+		}
+		ancestor := key[:toZoom]
+		if acc, ok := out[ancestor]; ok {
+			v = reduce(acc, v)
+		}
+		out[ancestor] = v
+	}
+	return out
+}
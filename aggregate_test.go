@@ -0,0 +1,121 @@
+package quadkey
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator(8)
+	a.Add(orb.Point{139.70, 35.60})
+	a.AddLonLat(139.71, 35.61)
+	a.AddSeq(slices.Values([]orb.Point{{-0.12, 51.5}, {139.72, 35.62}}))
+
+	tokyo, london := FromLonLat(139.7, 35.6, 8), FromLonLat(-0.12, 51.5, 8)
+	if got := a.Count(tokyo); got != 3 {
+		t.Fatalf("tokyo: got %d, want 3", got)
+	}
+	if got := a.Count(london); got != 1 {
+		t.Fatalf("london: got %d, want 1", got)
+	}
+	if a.Len() != 2 || a.Count("0") != 0 {
+		t.Fatalf("got %d tiles, count of 0 is %d", a.Len(), a.Count("0"))
+	}
+	keys := a.Keys()
+	if want := []QuadKey{london, tokyo}; !slices.Equal(keys, want) {
+		t.Fatalf("got keys %v, want %v", keys, want)
+	}
+
+	counts := a.Counts()
+	counts[tokyo] = 0
+	if a.Count(tokyo) != 3 {
+		t.Fatalf("Counts must return a copy")
+	}
+
+	fc := a.ToFeatureCollection()
+	if len(fc.Features) != 2 || fc.Features[1].ID != string(tokyo) || fc.Features[1].Properties["count"] != int64(3) {
+		t.Fatalf("unexpected collection %v", fc.Features)
+	}
+
+	if z := NewAggregator(0).Zoom(); z != 1 {
+		t.Fatalf("zoom 0: got %d, want 1", z)
+	}
+	if z := NewAggregator(40).Zoom(); z != MaxZoom {
+		t.Fatalf("zoom 40: got %d, want %d", z, MaxZoom)
+	}
+}
+
+func TestRollup(t *testing.T) {
+	values := map[QuadKey]float64{
+		"0120": 1, "0121": 2, "0133": 4,
+		"013":  8,
+		"3001": 16,
+		"0":    32, // coarser than the target zoom
+		"01a":  64, // invalid
+	}
+	got := Rollup(values, 2, nil)
+	if want := map[QuadKey]float64{"01": 15, "30": 16}; !maps.Equal(got, want) {
+		t.Fatalf("sum: got %v, want %v", got, want)
+	}
+	got = Rollup(values, 3, math.Max)
+	if want := map[QuadKey]float64{"012": 2, "013": 8, "300": 16}; !maps.Equal(got, want) {
+		t.Fatalf("max: got %v, want %v", got, want)
+	}
+	if got := Rollup(values, 0, nil); len(got) != 0 {
+		t.Fatalf("zoom 0: got %v", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	type minMax struct{ min, max float64 }
+	stats := NewStats(func(acc, v minMax) minMax {
+		return minMax{math.Min(acc.min, v.min), math.Max(acc.max, v.max)}
+	})
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() {
+			v := float64(i)
+			stats.Add(QuadKey("0"+strconv.Itoa(i%2)), minMax{v, v})
+		})
+	}
+	wg.Wait()
+	stats.Add("01a", minMax{-1, -1})
+
+	if stats.Len() != 2 {
+		t.Fatalf("got %d keys, want 2", stats.Len())
+	}
+	if v, ok := stats.Get("01"); !ok || v != (minMax{1, 99}) {
+		t.Fatalf("01: got %v, %v", v, ok)
+	}
+	if _, ok := stats.Get("3"); ok {
+		t.Fatalf("expected no value for 3")
+	}
+
+	fc := stats.ToFeatureCollection(func(v minMax) geojson.Properties {
+		return geojson.Properties{"min": v.min, "max": v.max}
+	})
+	if len(fc.Features) != 2 || fc.Features[0].ID != "00" || fc.Features[0].Properties["max"] != 98.0 {
+		t.Fatalf("unexpected collection %v", fc.Features)
+	}
+
+	var b strings.Builder
+	err := stats.WriteCSV(&b, []string{"min", "max"}, func(v minMax) []string {
+		return []string{strconv.FormatFloat(v.min, 'f', -1, 64), strconv.FormatFloat(v.max, 'f', -1, 64)}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	if lines[0] != "key,z,x,y,west,south,east,north,min,max" || !strings.HasPrefix(lines[1], "00,2,0,0,") || !strings.HasSuffix(lines[2], ",1,99") {
+		t.Fatalf("unexpected CSV:\n%s", b.String())
+	}
+}
@@ -0,0 +1,204 @@
+// Command quadkey converts between coordinates, tiles and quadkeys and walks
+// the tile hierarchy from the command line, for debugging tile data without
+// throwaway scripts.
+//
+// Usage:
+//
+//	quadkey encode LON LAT ZOOM  key of the tile holding a point
+//	quadkey decode KEY           zoom, tile coordinates and bound of a key
+//	quadkey parent KEY           key one zoom up
+//	quadkey children KEY         the four keys one zoom down
+//	quadkey neighbors KEY        the surrounding keys at the same zoom
+//	quadkey bound KEY            west,south,east,north of a key
+//	quadkey geojson [KEY...]     FeatureCollection of the keys
+//
+// geojson reads whitespace-separated keys from standard input when none are
+// given as arguments. Keys may be quadkeys or z/x/y tile paths.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	quadkey "github.com/nideojp/go-quadkey"
+)
+
+const usage = `usage: quadkey <command> [arguments]
+
+commands:
+  encode LON LAT ZOOM  key of the tile holding a point
+  decode KEY           zoom, tile coordinates and bound of a key
+  parent KEY           key one zoom up
+  children KEY         the four keys one zoom down
+  neighbors KEY        the surrounding keys at the same zoom
+  bound KEY            west,south,east,north of a key
+  geojson [KEY...]     FeatureCollection of the keys (default: read stdin)
+`
+
+// errUsage reports bad arguments; run prints the usage text for it.
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes one command and returns the process exit code: 0 on success,
+// 1 on failure and 2 on bad usage.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "encode":
+		err = encode(stdout, args)
+	case "decode":
+		err = withKey(args, func(key quadkey.QuadKey) error {
+			x, y, z := key.XYZ()
+			fmt.Fprintf(stdout, "z=%d x=%d y=%d bound=%s\n", z, x, y, formatBound(key))
+			return nil
+		})
+	case "parent":
+		err = withKey(args, func(key quadkey.QuadKey) error {
+			parent, err := key.Parent()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(stdout, parent)
+			return nil
+		})
+	case "children":
+		err = withKey(args, func(key quadkey.QuadKey) error {
+			children, err := key.ChildrenChecked()
+			if err != nil {
+				return err
+			}
+			return printKeys(stdout, children)
+		})
+	case "neighbors":
+		err = withKey(args, func(key quadkey.QuadKey) error {
+			return printKeys(stdout, key.Neighbors())
+		})
+	case "bound":
+		err = withKey(args, func(key quadkey.QuadKey) error {
+			fmt.Fprintln(stdout, formatBound(key))
+			return nil
+		})
+	case "geojson":
+		err = writeGeoJSON(stdin, stdout, args)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+	default:
+		fmt.Fprintf(stderr, "quadkey: unknown command %q\n\n%s", cmd, usage)
+		return 2
+	}
+
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprint(stderr, usage)
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "quadkey: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// encode prints the key of the tile holding LON LAT at ZOOM.
+func encode(w io.Writer, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	lon, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid longitude %q", args[0])
+	}
+	lat, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return fmt.Errorf("invalid latitude %q", args[1])
+	}
+	zoom, err := strconv.Atoi(args[2])
+	if err != nil || zoom < 1 || zoom > quadkey.MaxZoom {
+		return fmt.Errorf("zoom %q is out of range [1, %d]", args[2], quadkey.MaxZoom)
+	}
+	fmt.Fprintln(w, quadkey.FromLonLat(lon, lat, zoom))
+	return nil
+}
+
+// writeGeoJSON prints the FeatureCollection of the keys in args, or of those
+// read from r if args is empty.
+func writeGeoJSON(r io.Reader, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		sc := bufio.NewScanner(r)
+		sc.Split(bufio.ScanWords)
+		for sc.Scan() {
+			args = append(args, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]quadkey.QuadKey, 0, len(args))
+	for _, arg := range args {
+		key, err := parseKey(arg)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	data, err := quadkey.ToFeatureCollection(keys...).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", data)
+	return nil
+}
+
+// withKey parses the single key argument and passes it to fn.
+func withKey(args []string, fn func(quadkey.QuadKey) error) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	key, err := parseKey(args[0])
+	if err != nil {
+		return err
+	}
+	return fn(key)
+}
+
+// parseKey accepts a quadkey or a z/x/y tile path.
+func parseKey(s string) (quadkey.QuadKey, error) {
+	if strings.Contains(s, "/") {
+		return quadkey.FromTilePath(s)
+	}
+	return quadkey.FromKey(s)
+}
+
+// printKeys prints one key per line.
+func printKeys(w io.Writer, keys []quadkey.QuadKey) error {
+	for _, key := range keys {
+		if _, err := fmt.Fprintln(w, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatBound formats the key's bound as west,south,east,north.
+func formatBound(key quadkey.QuadKey) string {
+	b := key.Bound()
+	return strings.Join([]string{
+		strconv.FormatFloat(b.Min[0], 'f', -1, 64),
+		strconv.FormatFloat(b.Min[1], 'f', -1, 64),
+		strconv.FormatFloat(b.Max[0], 'f', -1, 64),
+		strconv.FormatFloat(b.Max[1], 'f', -1, 64),
+	}, ",")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args  []string
+		stdin string
+		code  int
+		out   string
+	}{
+		{[]string{"encode", "139.7", "35.6", "8"}, "", 0, "13300211\n"},
+		{[]string{"decode", "1"}, "", 0, "z=1 x=1 y=0 bound=0,0,180,85.05112877980659\n"},
+		{[]string{"decode", "1/1/0"}, "", 0, "z=1 x=1 y=0 bound=0,0,180,85.05112877980659\n"},
+		{[]string{"parent", "0231"}, "", 0, "023\n"},
+		{[]string{"children", "03"}, "", 0, "030\n031\n032\n033\n"},
+		{[]string{"children", strings.Repeat("3", 32)}, "", 1, ""},
+		{[]string{"neighbors", "0"}, "", 0, "1\n3\n2\n"},
+		{[]string{"bound", "3"}, "", 0, "0,-85.05112877980659,180,0\n"},
+		{[]string{"parent", "0"}, "", 1, ""},
+		{[]string{"decode", "01a"}, "", 1, ""},
+		{[]string{"encode", "1", "2", "33"}, "", 1, ""},
+		{[]string{"bound"}, "", 2, ""},
+		{[]string{"frobnicate"}, "", 2, ""},
+		{nil, "", 2, ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr strings.Builder
+		code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+		if code != tt.code || stdout.String() != tt.out {
+			t.Fatalf("%v: got code %d, output %q (stderr %q), want %d, %q", tt.args, code, stdout.String(), stderr.String(), tt.code, tt.out)
+		}
+		if code != 0 && stderr.Len() == 0 {
+			t.Fatalf("%v: expected a message on stderr", tt.args)
+		}
+	}
+}
+
+func TestRunGeoJSON(t *testing.T) {
+	var stdout, stderr strings.Builder
+	if code := run([]string{"geojson"}, strings.NewReader("0\n13 2/1/1\n"), &stdout, &stderr); code != 0 {
+		t.Fatalf("got code %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, id := range []string{`"id":"0"`, `"id":"13"`, `"id":"03"`} {
+		if !strings.Contains(out, id) {
+			t.Fatalf("output lacks %s: %s", id, out)
+		}
+	}
+	if code := run([]string{"geojson", "0", "4"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected failure for an invalid key, got code %d", code)
+	}
+}
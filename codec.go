@@ -0,0 +1,294 @@
+package quadkey
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// --------------------------
+// key set encoding
+// --------------------------
+
+// keysFormatVersion is the first byte of MarshalKeys output.
+const keysFormatVersion = 1
+
+// MarshalKeys encodes keys compactly at 2 bits per digit, about a quarter of
+// the size of a JSON string array. Keys are grouped by zoom, so each key
+// costs only its digits; the zoom and count of every group are stored once.
+// Duplicate keys are encoded once. An error is returned if any key is invalid.
+//
+// The format is a version byte followed, for each zoom present, by the zoom
+// and key count as uvarints and the keys' digits packed most significant bit
+// first, padded to a whole byte. Zooms ascend and keys within a group are in
+// quadkey order, so every key set has exactly one encoding.
+func MarshalKeys(keys []QuadKey) ([]byte, error) {
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := slices.Clone(keys)
+	slices.SortFunc(sorted, func(a, b QuadKey) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return cmp.Compare(a, b)
+	})
+	sorted = slices.Compact(sorted)
+
+	data := []byte{keysFormatVersion}
+	for i := 0; i < len(sorted); {
+		z := len(sorted[i])
+		j := i
+		for j < len(sorted) && len(sorted[j]) == z {
+			j++
+		}
+
+		data = binary.AppendUvarint(data, uint64(z))
+		data = binary.AppendUvarint(data, uint64(j-i))
+		var acc byte
+		bits := 0
+		for _, key := range sorted[i:j] {
+			for k := 0; k < z; k++ {
+				acc = acc<<2 | (key[k] - '0')
+				if bits += 2; bits == 8 {
+					data = append(data, acc)
+					acc, bits = 0, 0
+				}
+			}
+		}
+		if bits > 0 {
+			data = append(data, acc<<(8-bits))
+		}
+		i = j
+	}
+	return data, nil
+}
+
+// UnmarshalKeys decodes the output of MarshalKeys. Keys come back sorted by
+// zoom, then in quadkey order within each zoom. Only the canonical form is
+// accepted: an error is returned if a group's zoom is out of range or not
+// above the previous one, a group is empty, its keys are not strictly
+// increasing, or its padding bits are set.
+func UnmarshalKeys(data []byte) ([]QuadKey, error) {
+	if len(data) == 0 {
+		return nil, errors.New("key data is empty")
+	}
+	if data[0] != keysFormatVersion {
+		return nil, fmt.Errorf("unknown key data version %d", data[0])
+	}
+	data = data[1:]
+
+	keys := []QuadKey{}
+	prev := uint64(0)
+	for len(data) > 0 {
+		z, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("key data is truncated")
+		}
+		data = data[n:]
+		count, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("key data is truncated")
+		}
+		data = data[n:]
+		if z < 1 || z > MaxZoom {
+			return nil, fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxZoom)
+		}
+		if z <= prev {
+			return nil, fmt.Errorf("zoom %d group follows zoom %d", z, prev)
+		}
+		prev = z
+
+		// Check the size before allocating, so corrupt counts fail fast.
+		if count > uint64(len(data))*4/z {
+			return nil, errors.New("key data is truncated")
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("zoom %d group is empty", z)
+		}
+
+		buf := make([]byte, z)
+		for i := uint64(0); i < count; i++ {
+			for k := uint64(0); k < z; k++ {
+				bit := (i*z + k) * 2
+				buf[k] = '0' + data[bit/8]>>(6-bit%8)&3
+			}
+			if i > 0 && string(buf) <= string(keys[len(keys)-1]) {
+				return nil, fmt.Errorf("zoom %d group is not in strictly increasing order", z)
+			}
+			keys = append(keys, QuadKey(buf))
+		}
+		size := (count*z*2 + 7) / 8
+		if pad := (8 - count*z*2%8) % 8; data[size-1]&(1<<pad-1) != 0 {
+			return nil, errors.New("key data has trailing bits set")
+		}
+		data = data[size:]
+	}
+	return keys, nil
+}
+
+// --------------------------
+// binary encoding
+// --------------------------
+
+// MarshalBinary implements encoding.BinaryMarshaler: a zoom byte followed by
+// the digits packed at 2 bits each, most significant bit first and padded
+// with zeros to a whole byte, so a zoom 16 key takes 5 bytes. An error is
+// returned if the key is invalid.
+func (key QuadKey) MarshalBinary() ([]byte, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	z := key.Z()
+	data := make([]byte, 1+(2*z+7)/8)
+	data[0] = byte(z)
+	for i := 0; i < z; i++ {
+		data[1+i/4] |= (key[i] - '0') << (6 - 2*(i%4))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding the output
+// of MarshalBinary. An error is returned if the zoom is out of range, the
+// length does not match the zoom, or the padding bits are set.
+func (key *QuadKey) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("binary key is empty")
+	}
+	z := int(data[0])
+	if z < 1 || z > MaxZoom {
+		return fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxZoom)
+	}
+	if len(data) != 1+(2*z+7)/8 {
+		return fmt.Errorf("binary key has %d bytes, want %d for zoom %d", len(data), 1+(2*z+7)/8, z)
+	}
+	if pad := (8 - 2*z%8) % 8; data[len(data)-1]&(1<<pad-1) != 0 {
+		return errors.New("binary key has trailing bits set")
+	}
+
+	buf := make([]byte, z)
+	for i := range buf {
+		buf[i] = '0' + data[1+i/4]>>(6-2*(i%4))&3
+	}
+	*key = QuadKey(buf)
+	return nil
+}
+
+// --------------------------
+// database/sql interop
+// --------------------------
+
+// Scan implements sql.Scanner, reading a key from a TEXT, VARCHAR or BYTEA
+// column holding its digits. The key is validated as FromKey does. NULL is
+// rejected; scan nullable columns into a sql.Null[QuadKey].
+func (key *QuadKey) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case nil:
+		return errors.New("cannot scan NULL into a QuadKey")
+	default:
+		return fmt.Errorf("cannot scan %T into a QuadKey", src)
+	}
+	value, err := FromKey(text)
+	if err != nil {
+		return err
+	}
+	*key = value
+	return nil
+}
+
+// Value implements driver.Valuer, writing the key as its digit string. An
+// error is returned if the key is invalid.
+func (key QuadKey) Value() (driver.Value, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	return string(key), nil
+}
+
+// --------------------------
+// short string encoding
+// --------------------------
+
+// shortAlphabet is Crockford's base32 in lower case: URL-safe, free of the
+// easily confused i, l, o and u, and in ASCII order, so encodings of one
+// zoom sort like their keys.
+const shortAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// maxShortZoom is the deepest zoom one shortAlphabet character can name.
+const maxShortZoom = len(shortAlphabet) - 1
+
+// EncodeShort returns a short, URL-safe form of the key: one character for
+// the zoom, then the digits at 2 bits each in base32, so a zoom 20 key takes
+// 9 characters instead of 20. An error is returned if the key is invalid or
+// deeper than zoom 31.
+func (key QuadKey) EncodeShort() (string, error) {
+	if err := key.Valid(); err != nil {
+		return "", err
+	}
+	z := key.Z()
+	if z > maxShortZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, maxShortZoom)
+	}
+
+	var b strings.Builder
+	b.Grow(1 + (2*z+4)/5)
+	b.WriteByte(shortAlphabet[z])
+	acc, bits := 0, 0
+	for i := 0; i < z; i++ {
+		acc = acc<<2 | int(key[i]-'0')
+		if bits += 2; bits >= 5 {
+			bits -= 5
+			b.WriteByte(shortAlphabet[acc>>bits&31])
+			acc &= 1<<bits - 1
+		}
+	}
+	if bits > 0 {
+		b.WriteByte(shortAlphabet[acc<<(5-bits)&31])
+	}
+	return b.String(), nil
+}
+
+// DecodeShort parses the output of EncodeShort.
+func DecodeShort(s string) (QuadKey, error) {
+	if s == "" {
+		return "", errors.New("short key is empty")
+	}
+	z := strings.IndexByte(shortAlphabet, s[0])
+	if z < 1 {
+		return "", fmt.Errorf("short key %q has an invalid zoom", s)
+	}
+	if len(s) != 1+(2*z+4)/5 {
+		return "", fmt.Errorf("short key %q has the wrong length for zoom %d", s, z)
+	}
+
+	key := make([]byte, 0, z)
+	acc, bits := 0, 0
+	for i := 1; i < len(s); i++ {
+		v := strings.IndexByte(shortAlphabet, s[i])
+		if v < 0 {
+			return "", fmt.Errorf("short key %q contains invalid character %q", s, s[i])
+		}
+		acc, bits = acc<<5|v, bits+5
+		for bits >= 2 && len(key) < z {
+			bits -= 2
+			key = append(key, '0'+byte(acc>>bits&3))
+		}
+		acc &= 1<<bits - 1
+	}
+	if acc&(1<<bits-1) != 0 {
+		return "", fmt.Errorf("short key %q has trailing bits set", s)
+	}
+	return QuadKey(key), nil
+}
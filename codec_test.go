@@ -0,0 +1,243 @@
+package quadkey
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestMarshalKeysRoundTrip(t *testing.T) {
+	keys := []QuadKey{"3", "0123", "1", "01", "0123", "33333", "0", "21"}
+	data, err := MarshalKeys(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := UnmarshalKeys(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []QuadKey{"0", "1", "3", "01", "21", "0123", "33333"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMarshalKeysSize(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{140.0, 36.0}}
+	keys := KeysInBound(bound, 16)
+	data, err := MarshalKeys(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 16 digits at 2 bits each is 4 bytes per key, plus a small header.
+	if want := 4*len(keys) + 8; len(data) > want {
+		t.Fatalf("encoded %d keys in %d bytes, want at most %d", len(keys), len(data), want)
+	}
+	js, _ := json.Marshal(keys)
+	if len(data)*4 > len(js) {
+		t.Fatalf("binary %d bytes is not a quarter of JSON %d bytes", len(data), len(js))
+	}
+
+	got, err := UnmarshalKeys(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(got, keys) {
+		t.Fatalf("round trip differs")
+	}
+}
+
+func TestMarshalKeysEmpty(t *testing.T) {
+	data, err := MarshalKeys(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := UnmarshalKeys(data)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("got %v, err %v", got, err)
+	}
+
+	if _, err := MarshalKeys([]QuadKey{"012", ""}); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+}
+
+func TestUnmarshalKeysInvalid(t *testing.T) {
+	valid, _ := MarshalKeys([]QuadKey{"0123", "3210"})
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"version":     {9},
+		"truncated":   valid[:len(valid)-1],
+		"zoom 0":      {keysFormatVersion, 0, 1, 0},
+		"zoom 33":     {keysFormatVersion, MaxZoom + 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		"huge zoom":   {keysFormatVersion, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 1, 0},
+		"count":       {keysFormatVersion, 4, 0xff, 0xff, 0xff, 0xff, 0x0f},
+		"varint":      {keysFormatVersion, 0x80},
+		"padding":     {keysFormatVersion, 3, 1, 0b00011011},
+		"padding 2":   {keysFormatVersion, 1, 3, 0b11100001, 2, 1, 0},
+		"empty group": {keysFormatVersion, 1, 0, 2, 1, 0},
+		"zoom down":   {keysFormatVersion, 2, 1, 0, 1, 1, 0},
+		"zoom twice":  {keysFormatVersion, 1, 1, 0, 1, 1, 0b01000000},
+		"duplicate":   {keysFormatVersion, 2, 2, 0b00010001},
+		"unsorted":    {keysFormatVersion, 2, 2, 0b01000001},
+	} {
+		if _, err := UnmarshalKeys(data); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, key := range []QuadKey{"0", "3", "0123", "13300211", "132", "1320012301230123", QuadKey(strings.Repeat("3", MaxZoom))} {
+		data, err := key.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", key, err)
+		}
+		if want := 1 + (2*key.Z()+7)/8; len(data) != want {
+			t.Fatalf("%q: got %d bytes, want %d", key, len(data), want)
+		}
+		var got QuadKey
+		if err := got.UnmarshalBinary(data); err != nil || got != key {
+			t.Fatalf("%q: round trip got %q, %v", key, got, err)
+		}
+	}
+
+	data, _ := QuadKey("0123").MarshalBinary()
+	if !slices.Equal(data, []byte{4, 0b00011011}) {
+		t.Fatalf("got %08b", data)
+	}
+
+	// Keys survive gob, which uses the binary form.
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(QuadKey("13300211")); err != nil {
+		t.Fatalf("gob encode: %v", err)
+	}
+	var decoded QuadKey
+	if err := gob.NewDecoder(&b).Decode(&decoded); err != nil || decoded != "13300211" {
+		t.Fatalf("gob: got %q, %v", decoded, err)
+	}
+}
+
+func TestBinaryInvalid(t *testing.T) {
+	if _, err := QuadKey("01a").MarshalBinary(); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	for _, data := range [][]byte{
+		nil,
+		{0},                                      // zoom 0
+		{MaxZoom + 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, // beyond MaxZoom
+		{4},                                      // missing digits
+		{4, 0x1b, 0},                             // extra byte
+		{3, 0x1b},                                // padding bits set
+	} {
+		key := QuadKey("0")
+		if err := key.UnmarshalBinary(data); err == nil {
+			t.Fatalf("%v: expected error, got %q", data, key)
+		}
+		if key != "0" {
+			t.Fatalf("%v: key changed to %q on error", data, key)
+		}
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	for _, src := range []any{"13300211", []byte("13300211")} {
+		var key QuadKey
+		if err := key.Scan(src); err != nil || key != "13300211" {
+			t.Fatalf("Scan(%T): got %q, %v", src, key, err)
+		}
+	}
+	for _, src := range []any{"", "01a3", []byte("0124"), nil, 42} {
+		key := QuadKey("0")
+		if err := key.Scan(src); err == nil {
+			t.Fatalf("Scan(%#v): expected error, got %q", src, key)
+		}
+		if key != "0" {
+			t.Fatalf("Scan(%#v): key changed to %q on error", src, key)
+		}
+	}
+
+	if v, err := QuadKey("0231").Value(); err != nil || v != "0231" {
+		t.Fatalf("Value: got %#v, %v", v, err)
+	}
+	if _, err := QuadKey("01a").Value(); err == nil {
+		t.Fatalf("Value: expected error for an invalid key")
+	}
+
+	// sql.Null handles nullable columns.
+	var null sql.Null[QuadKey]
+	if err := null.Scan(nil); err != nil || null.Valid {
+		t.Fatalf("sql.Null: got %+v, %v", null, err)
+	}
+	if err := null.Scan("0231"); err != nil || !null.Valid || null.V != "0231" {
+		t.Fatalf("sql.Null: got %+v, %v", null, err)
+	}
+}
+
+func TestEncodeShort(t *testing.T) {
+	tests := []struct {
+		key  QuadKey
+		want string
+	}{
+		{"0", "10"},
+		{"3", "1r"},
+		{"0123", "43c"},
+		{"33333", "5zz"},
+	}
+	for _, tt := range tests {
+		got, err := tt.key.EncodeShort()
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: got %q, err %v, want %q", tt.key, got, err, tt.want)
+		}
+	}
+
+	prev := ""
+	for d := range QuadKey("132").DescendantsAtZoom(9) {
+		s, err := d.EncodeShort()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", d, err)
+		}
+		if len(s) != 5 {
+			t.Fatalf("%q: got %q, want 5 characters", d, s)
+		}
+		if s <= prev {
+			t.Fatalf("%q: %q does not sort after %q", d, s, prev)
+		}
+		prev = s
+		if got, err := DecodeShort(s); err != nil || got != d {
+			t.Fatalf("round trip of %q: got %q, err %v", d, got, err)
+		}
+	}
+
+	deep := QuadKey("3120312031203120312031203120312")
+	s, err := deep.EncodeShort()
+	if err != nil || len(s) != 14 {
+		t.Fatalf("zoom 31: got %q, err %v", s, err)
+	}
+	if got, _ := DecodeShort(s); got != deep {
+		t.Fatalf("zoom 31 round trip: got %q", got)
+	}
+}
+
+func TestShortInvalid(t *testing.T) {
+	if _, err := QuadKey("01a").EncodeShort(); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := QuadKey("00000000000000000000000000000000").EncodeShort(); err == nil {
+		t.Fatalf("expected error for zoom 32")
+	}
+	for _, s := range []string{"", "0", "1", "100", "1u", "43d", "4!c"} {
+		if _, err := DecodeShort(s); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
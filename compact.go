@@ -0,0 +1,150 @@
+package quadkey
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// --------------------------
+// compaction
+// --------------------------
+
+// Compact returns the smallest set of keys covering the same tiles as keys,
+// sorted in quadkey order: duplicates and keys contained in another key are
+// dropped, and every complete quartet of siblings is replaced by its parent,
+// recursively. Zoom 1 quartets are kept, as the world has no key. An error is
+// returned if any key is invalid.
+func Compact(keys []QuadKey) ([]QuadKey, error) {
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+
+	// In sorted order a key follows its ancestors and siblings follow each
+	// other in digit order, so quartets complete at the top of the stack.
+	out := make([]QuadKey, 0, len(sorted))
+	for _, key := range sorted {
+		if n := len(out); n > 0 && strings.HasPrefix(string(key), string(out[n-1])) {
+			continue
+		}
+		out = append(out, key)
+		for completesQuartet(out) {
+			last := out[len(out)-1]
+			out = append(out[:len(out)-4], last[:len(last)-1])
+		}
+	}
+	return out, nil
+}
+
+// decompactLimit is the most keys Decompact returns, about 16 million.
+const decompactLimit = 1 << 24
+
+// Decompact expands keys to zoom, the inverse of Compact: every key is
+// replaced by its descendants at zoom, sorted in quadkey order and
+// deduplicated. An error is returned if any key is invalid or finer than
+// zoom, or if zoom exceeds MaxZoom. The result grows by a factor of 4 per
+// zoom level expanded, so expansions beyond 2^24 keys return a
+// *TooManyTilesError; use DecompactLimit to pick another cap.
+func Decompact(keys []QuadKey, zoom int) ([]QuadKey, error) {
+	return DecompactLimit(keys, zoom, decompactLimit)
+}
+
+// DecompactLimit is Decompact with a cap on the result size. If the keys
+// expand to more than maxKeys tiles, counting duplicates, it returns a
+// *TooManyTilesError instead of allocating them.
+func DecompactLimit(keys []QuadKey, zoom int, maxKeys int) ([]QuadKey, error) {
+	if zoom > MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, MaxZoom)
+	}
+	var size uint64
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return nil, err
+		}
+		if key.Z() > zoom {
+			return nil, fmt.Errorf("key %q is finer than zoom %d", key, zoom)
+		}
+		// One key expands to at most 4^31 tiles, and the total saturates at
+		// 4^31, so the sum never overflows.
+		size = min(size+1<<(2*(zoom-key.Z())), 1<<62)
+	}
+	if size > uint64(max(maxKeys, 0)) {
+		return nil, &TooManyTilesError{Count: int64(size), Limit: maxKeys}
+	}
+
+	out := make([]QuadKey, 0, size)
+	for _, key := range keys {
+		for d := range key.DescendantsAtZoom(zoom) {
+			out = append(out, d)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+// --------------------------
+// membership
+// --------------------------
+
+// Covers reports whether key or one of its ancestors is in keys, which must
+// be sorted in quadkey order, such as the output of Compact or Cover. Each
+// of the key's zooms costs one binary search.
+func Covers(keys []QuadKey, key QuadKey) bool {
+	_, ok := coveringKey(keys, key)
+	return ok
+}
+
+// CoversDescendant reports whether key or one of its descendants is in keys,
+// which must be sorted in quadkey order.
+func CoversDescendant(keys []QuadKey, key QuadKey) bool {
+	if key.Valid() != nil {
+		return false
+	}
+	// Descendants sort directly after the key.
+	i, _ := slices.BinarySearch(keys, key)
+	return i < len(keys) && strings.HasPrefix(string(keys[i]), string(key))
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// coveringKey returns the coarsest of key and its ancestors in the sorted
+// keys, if any.
+func coveringKey(keys []QuadKey, key QuadKey) (QuadKey, bool) {
+	if key.Valid() != nil {
+		return "", false
+	}
+	for z := 1; z <= key.Z(); z++ {
+		if _, ok := slices.BinarySearch(keys, key[:z]); ok {
+			return key[:z], true
+		}
+	}
+	return "", false
+}
+
+// completesQuartet reports whether the last four keys are the four children
+// of one parent below zoom 1, in digit order.
+func completesQuartet(keys []QuadKey) bool {
+	n := len(keys)
+	if n < 4 {
+		return false
+	}
+	last := keys[n-1]
+	z := len(last)
+	if z < 2 {
+		return false
+	}
+	for i, digit := range []byte("0123") {
+		k := keys[n-4+i]
+		if len(k) != z || k[z-1] != digit || k[:z-1] != last[:z-1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package quadkey
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []QuadKey
+		want []QuadKey
+	}{
+		{"quartet", []QuadKey{"0123", "0120", "0122", "0121"}, []QuadKey{"012"}},
+		{"incomplete", []QuadKey{"0120", "0121", "0123"}, []QuadKey{"0120", "0121", "0123"}},
+		{"recursive", append(slices.Collect(QuadKey("01").DescendantsAtZoom(4)), "00", "02", "03"), []QuadKey{"0"}},
+		{"duplicates", []QuadKey{"0120", "0120", "012"}, []QuadKey{"012"}},
+		{"contained", []QuadKey{"1", "1023", "10", "2"}, []QuadKey{"1", "2"}},
+		{"zoom 1 quartet", []QuadKey{"3", "1", "0", "2"}, []QuadKey{"0", "1", "2", "3"}},
+		{"mixed zoom quartet", []QuadKey{"130", "131", "1320", "1321", "1322", "1323", "133"}, []QuadKey{"13"}},
+		{"empty", nil, []QuadKey{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compact(tt.keys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Compact([]QuadKey{"012", "01a"}); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+}
+
+func TestCompactRoundTrip(t *testing.T) {
+	poly := orb.Polygon{{{139.0, 35.0}, {140.5, 35.2}, {140.0, 36.5}, {139.2, 36.0}, {139.0, 35.0}}}
+	keys := Cover(poly, 12)
+
+	compacted, err := Compact(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(compacted) >= len(keys) {
+		t.Fatalf("expected compaction, got %d keys from %d", len(compacted), len(keys))
+	}
+	assertSorted(t, "compacted", compacted)
+
+	got, err := Decompact(compacted, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, keys) {
+		t.Fatalf("round trip: got %d keys, want %d", len(got), len(keys))
+	}
+}
+
+func TestDecompact(t *testing.T) {
+	got, err := Decompact([]QuadKey{"31", "0", "312"}, 2)
+	if err == nil {
+		t.Fatalf("expected error for a key finer than the zoom, got %v", got)
+	}
+
+	got, err = Decompact([]QuadKey{"31", "0", "3"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []QuadKey{"00", "01", "02", "03", "30", "31", "32", "33"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err := Decompact([]QuadKey{""}, 3); err == nil {
+		t.Fatalf("expected error for an empty key")
+	}
+	if _, err := Decompact([]QuadKey{"0"}, MaxZoom+1); err == nil {
+		t.Fatalf("expected error for a zoom beyond MaxZoom")
+	}
+
+	// Huge expansions fail before allocating, even when the count would
+	// overflow.
+	var tooMany *TooManyTilesError
+	if _, err := Decompact([]QuadKey{"0"}, 30); !errors.As(err, &tooMany) || tooMany.Count != 1<<58 || tooMany.Limit != decompactLimit {
+		t.Fatalf("zoom 30: got %v", err)
+	}
+	if _, err := Decompact([]QuadKey{"0", "1", "2", "3"}, MaxZoom); !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("zoom %d: got %v", MaxZoom, err)
+	}
+	if got, err := DecompactLimit([]QuadKey{"0", "1"}, 3, 32); err != nil || len(got) != 32 {
+		t.Fatalf("DecompactLimit at the cap: got %d keys, %v", len(got), err)
+	}
+	if _, err := DecompactLimit([]QuadKey{"0", "1"}, 3, 31); !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("DecompactLimit over the cap: got %v", err)
+	}
+}
+
+func TestCovers(t *testing.T) {
+	keys, _ := Compact([]QuadKey{"0", "120", "121", "122", "123", "1302", "2", "21"})
+	tests := []struct {
+		key        QuadKey
+		covers     bool
+		descendant bool
+	}{
+		{"0", true, true},
+		{"0123", true, false},
+		{"12", true, true},
+		{"1213", true, false},
+		{"1", false, true},
+		{"13", false, true},
+		{"130", false, true},
+		{"1302", true, true},
+		{"13021", true, false},
+		{"1303", false, false},
+		{"3", false, false},
+		{"21", true, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		if got := Covers(keys, tt.key); got != tt.covers {
+			t.Fatalf("Covers(%q): got %v, want %v", tt.key, got, tt.covers)
+		}
+		if got := CoversDescendant(keys, tt.key); got != tt.descendant {
+			t.Fatalf("CoversDescendant(%q): got %v, want %v", tt.key, got, tt.descendant)
+		}
+	}
+
+	// Overlapping keys work too, as long as they are sorted.
+	if !Covers([]QuadKey{"0", "01", "02"}, "03") {
+		t.Fatalf("expected %q to be covered by %q", "03", "0")
+	}
+}
@@ -0,0 +1,833 @@
+package quadkey
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// --------------------------
+// coverage
+// --------------------------
+
+// Cover returns the tiles at zoom that intersect the geometry, sorted in
+// quadkey order. Every orb geometry type is supported:
+//
+//   - Point and MultiPoint cover the tiles FromPoint returns.
+//   - LineString and MultiLineString cover every tile the lines pass through.
+//   - Ring, Polygon and MultiPolygon cover like KeysInPolygon.
+//   - Bound covers like KeysInBound, with half-open edges.
+//   - Collection covers the union of its members.
+//
+// A nil geometry or a zoom outside [1, MaxZoom] returns no keys.
+func Cover(g orb.Geometry, zoom int) []QuadKey {
+	return CoverWithOptions(g, zoom, CoverOptions{})
+}
+
+// CoverOptions tunes how CoverWithOptions computes a covering.
+type CoverOptions struct {
+	// Workers is the number of goroutines covering the geometry, each one
+	// filling a stripe of tile rows. Values below 2 cover serially.
+	Workers int
+	// Progress, if set, is called as tiles are found; see ProgressFunc.
+	Progress ProgressFunc
+}
+
+// ProgressFunc receives the number of distinct tiles found so far and an
+// estimate of the total, which never drops below emitted. It is called every
+// few thousand tiles and once more when the operation completes; emitted
+// never decreases between calls. Calls never overlap, even with several
+// workers. Canceled operations skip the final call.
+type ProgressFunc func(emitted, estimated int64)
+
+// progressInterval is the number of tiles between progress reports.
+const progressInterval = 4096
+
+// progress counts tiles for a ProgressFunc, possibly across goroutines.
+type progress struct {
+	fn        ProgressFunc
+	estimated int64
+	emitted   atomic.Int64
+	mu        sync.Mutex
+	reported  int64
+}
+
+func newProgress(fn ProgressFunc, estimated int64) *progress {
+	if fn == nil {
+		return nil
+	}
+	return &progress{fn: fn, estimated: estimated}
+}
+
+// add counts one tile, reporting every progressInterval tiles.
+func (p *progress) add() {
+	if p == nil {
+		return
+	}
+	if e := p.emitted.Add(1); e%progressInterval == 0 {
+		p.report(e, false)
+	}
+}
+
+// finish reports the final count.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	p.report(p.emitted.Load(), true)
+}
+
+func (p *progress) report(emitted int64, final bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Concurrent workers may reach the lock out of order; never go back.
+	if emitted <= p.reported && !final {
+		return
+	}
+	p.reported = emitted
+	p.fn(emitted, max(p.estimated, emitted))
+}
+
+// CoverWithOptions returns the same tiles as Cover, computed as opts asks.
+// With several workers the rows spanned by the geometry are split into
+// stripes covered concurrently, which pays off for large polygons at high
+// zooms where filling the interior dominates.
+func CoverWithOptions(g orb.Geometry, zoom int, opts CoverOptions) []QuadKey {
+	keys, _ := CoverCtx(context.Background(), g, zoom, opts)
+	return keys
+}
+
+// CoverCtx is CoverWithOptions that stops early when ctx is done, returning
+// nil and ctx.Err(). Pass CoverOptions{} to cover serially.
+func CoverCtx(ctx context.Context, g orb.Geometry, zoom int, opts CoverOptions) ([]QuadKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if zoom < 1 || zoom > MaxZoom || g == nil {
+		return []QuadKey{}, nil
+	}
+
+	gr := newGrid(zoom)
+	b := g.Bound()
+	empty := b.Min.Lat() > b.Max.Lat()
+	estimated := int64(0)
+	if !empty {
+		// The tiles of the bounding box are an upper bound for any geometry.
+		estimated = CountKeysInBound(b, zoom)
+	}
+	prog := newProgress(opts.Progress, estimated)
+
+	if opts.Workers < 2 || empty {
+		gr.done = ctx.Done()
+		gr.progress = prog
+		gr.add(g)
+	} else {
+		_, first := gr.project(b.Max)
+		_, last := gr.project(b.Min)
+		stripes := stripeRows(int(first), int(last), opts.Workers)
+		grids := make([]*grid, len(stripes))
+		var wg sync.WaitGroup
+		for i, rows := range stripes {
+			grids[i] = newGrid(zoom)
+			grids[i].rowMin, grids[i].rowMax = rows[0], rows[1]
+			grids[i].done = ctx.Done()
+			grids[i].progress = prog
+			wg.Go(func() { grids[i].add(g) })
+		}
+		wg.Wait()
+
+		// Stripes never share a row, so their cells can be merged directly.
+		for _, stripe := range grids {
+			maps.Copy(gr.cells, stripe.cells)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	prog.finish()
+	return gr.keys(), nil
+}
+
+// stripeRows splits the rows first through last into at most workers
+// contiguous stripes of near-equal height.
+func stripeRows(first, last, workers int) [][2]int {
+	rows := last - first + 1
+	workers = min(workers, rows)
+	stripes := make([][2]int, 0, workers)
+	for i := range workers {
+		stripes = append(stripes, [2]int{first + rows*i/workers, first + rows*(i+1)/workers - 1})
+	}
+	return stripes
+}
+
+// Coverage is a covering split by how each tile relates to the geometry.
+type Coverage struct {
+	// Interior holds tiles lying entirely inside an areal part of the
+	// geometry; point-in-polygon tests can be skipped for them.
+	Interior []QuadKey
+	// Boundary holds tiles touched by an edge, line or point, which may be
+	// only partially covered.
+	Boundary []QuadKey
+}
+
+// Keys returns all tiles of the coverage, sorted in quadkey order.
+func (c Coverage) Keys() []QuadKey {
+	keys := make([]QuadKey, 0, len(c.Interior)+len(c.Boundary))
+	keys = append(keys, c.Interior...)
+	keys = append(keys, c.Boundary...)
+	slices.Sort(keys)
+	return keys
+}
+
+// CoverClassified returns the same tiles as Cover, split into interior and
+// boundary tiles, each sorted in quadkey order. The split is conservative: a
+// tile is only reported as interior when it is known to lie entirely inside
+// the geometry.
+func CoverClassified(g orb.Geometry, zoom int) Coverage {
+	if zoom < 1 || zoom > MaxZoom {
+		return Coverage{Interior: []QuadKey{}, Boundary: []QuadKey{}}
+	}
+
+	gr := newGrid(zoom)
+	gr.add(g)
+	return gr.coverage()
+}
+
+// WeightedKey is a tile together with the fraction of its area covered by a
+// geometry, in (0, 1].
+type WeightedKey struct {
+	Key      QuadKey
+	Fraction float64
+}
+
+// CoverWeighted returns the tiles at zoom overlapping the areal parts of the
+// geometry (rings, polygons, multipolygons and bounds), each with the
+// fraction of the tile's area the geometry covers, sorted by key. Interior
+// tiles have a fraction of 1. Areas are measured in Web Mercator tile space;
+// overlapping parts are not merged, so their sum is capped at 1. Points and
+// lines have no area and contribute nothing.
+func CoverWeighted(g orb.Geometry, zoom int) []WeightedKey {
+	if zoom < 1 || zoom > MaxZoom {
+		return []WeightedKey{}
+	}
+
+	gr := newGrid(zoom)
+	parts := arealParts(g, nil)
+	for _, poly := range parts {
+		gr.addPolygon(poly)
+	}
+
+	// Project every ring once; each boundary tile clips them to itself.
+	projected := make([][][]vec, len(parts))
+	for i, poly := range parts {
+		for _, ring := range poly {
+			r := make([]vec, len(ring))
+			for j, p := range ring {
+				r[j].x, r[j].y = gr.project(p)
+			}
+			projected[i] = append(projected[i], r)
+		}
+	}
+
+	weighted := []WeightedKey{}
+	for _, key := range gr.keys() {
+		x, y, _ := key.XYZ()
+		if gr.cells[cell{x, y}] {
+			weighted = append(weighted, WeightedKey{Key: key, Fraction: 1})
+			continue
+		}
+
+		area := 0.0
+		for _, rings := range projected {
+			for i, ring := range rings {
+				a := math.Abs(ringArea(clipRing(ring, float64(x), float64(y), float64(x+1), float64(y+1))))
+				if i == 0 {
+					area += a
+				} else {
+					area -= a
+				}
+			}
+		}
+		if area > 0 {
+			weighted = append(weighted, WeightedKey{Key: key, Fraction: math.Min(area, 1)})
+		}
+	}
+	return weighted
+}
+
+// CoverFeature returns the tiles at zoom covering the feature's geometry, as
+// Cover does. A nil feature covers nothing.
+func CoverFeature(feature *geojson.Feature, zoom int) []QuadKey {
+	if feature == nil {
+		return []QuadKey{}
+	}
+	return Cover(feature.Geometry, zoom)
+}
+
+// CoverFeatureCollection returns the tiles at zoom covering any feature in the
+// collection, sorted and deduplicated.
+func CoverFeatureCollection(fc *geojson.FeatureCollection, zoom int) []QuadKey {
+	if fc == nil || zoom < 1 || zoom > MaxZoom {
+		return []QuadKey{}
+	}
+
+	g := newGrid(zoom)
+	for _, feature := range fc.Features {
+		if feature != nil {
+			g.add(feature.Geometry)
+		}
+	}
+	return g.keys()
+}
+
+// CoverEachFeature covers every feature in the collection separately. The
+// result is indexed like fc.Features.
+func CoverEachFeature(fc *geojson.FeatureCollection, zoom int) [][]QuadKey {
+	if fc == nil {
+		return [][]QuadKey{}
+	}
+
+	covers := make([][]QuadKey, len(fc.Features))
+	for i, feature := range fc.Features {
+		covers[i] = CoverFeature(feature, zoom)
+	}
+	return covers
+}
+
+// KeysAlongLine returns every tile at zoom that the line passes through, in
+// the order the line first enters them. Cells are found by walking the tile
+// grid segment by segment (a supercover), so a segment crossing exactly
+// through a tile corner also includes both tiles beside that corner and no
+// gaps appear on diagonals. Segments are straight in Web Mercator tile space.
+func KeysAlongLine(ls orb.LineString, zoom int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom {
+		return []QuadKey{}
+	}
+
+	g := newGrid(zoom)
+	g.addLineString(ls)
+	return g.orderedKeys()
+}
+
+// KeysInCircle returns the tiles at zoom that intersect the geodesic circle of
+// radiusMeters around center, sorted in quadkey order. A tile is included when
+// its nearest point lies within the radius on a sphere of radius
+// orb.EarthRadius. Circles crossing the antimeridian wrap around it.
+func KeysInCircle(center orb.Point, radiusMeters float64, zoom int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom || radiusMeters < 0 || math.IsNaN(radiusMeters) {
+		return []QuadKey{}
+	}
+
+	g := newGrid(zoom)
+	g.addCircle(center, radiusMeters)
+	return g.keys()
+}
+
+// CoverBuffered returns the tiles at zoom lying within bufferMeters of the
+// geometry, sorted in quadkey order: the tiles Cover returns plus those
+// reached by expanding every point, line and polygon outline by the buffer.
+// Lines are sampled at a quarter of the buffer distance, so the result is
+// conservative: it may include tiles up to 12.5% beyond the buffer, but never
+// misses one inside it. A buffer of 0 is the same as Cover.
+func CoverBuffered(g orb.Geometry, bufferMeters float64, zoom int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom || bufferMeters < 0 || math.IsNaN(bufferMeters) {
+		return []QuadKey{}
+	}
+
+	gr := newGrid(zoom)
+	gr.add(g)
+	if bufferMeters > 0 {
+		step := bufferMeters / 4
+		radius := bufferMeters + step/2
+		walkOutline(g, func(a, b orb.Point) {
+			// Consecutive samples are at most step apart, so every point of
+			// the segment is within step/2 of a sample.
+			n := max(1, int(math.Ceil(sampledLength(a, b)/step)))
+			for i := 0; i <= n; i++ {
+				f := float64(i) / float64(n)
+				gr.addCircle(orb.Point{a[0] + (b[0]-a[0])*f, a[1] + (b[1]-a[1])*f}, radius)
+			}
+		})
+	}
+	return gr.keys()
+}
+
+// KeysInPolygon returns the tiles at zoom that intersect the polygon, sorted
+// in quadkey order. Holes are respected: tiles lying entirely inside a hole
+// are left out. Edges are treated as straight lines in Web Mercator tile
+// space. As with KeysInBound, polygons crossing the dateline should be split
+// before calling.
+func KeysInPolygon(poly orb.Polygon, zoom int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom {
+		return []QuadKey{}
+	}
+
+	g := newGrid(zoom)
+	g.addPolygon(poly)
+	return g.keys()
+}
+
+// --------------------------
+// struct grid
+// --------------------------
+
+// cell is a tile position at a fixed zoom.
+type cell struct {
+	x, y int
+}
+
+// grid accumulates the tiles covering a geometry at one zoom. Coordinates are
+// fractional tile positions: tile (x, y) spans [x, x+1) × [y, y+1).
+type grid struct {
+	zoom int
+	n    float64
+	// cells maps each covered tile to whether it lies fully inside an areal
+	// geometry (true) or touches its boundary (false).
+	cells map[cell]bool
+	// order lists the cells in the order they were first marked.
+	order []cell
+	// rowMin and rowMax limit the rows marked, so a geometry can be covered
+	// in stripes.
+	rowMin, rowMax int
+	// done, when closed, stops further marking.
+	done <-chan struct{}
+	// progress, if set, counts newly marked cells.
+	progress *progress
+}
+
+func newGrid(zoom int) *grid {
+	return &grid{
+		zoom:   zoom,
+		n:      math.Exp2(float64(zoom)),
+		cells:  map[cell]bool{},
+		rowMax: 1<<zoom - 1,
+	}
+}
+
+// canceled reports whether the grid's done channel is closed.
+func (g *grid) canceled() bool {
+	select {
+	case <-g.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// project converts lon/lat to fractional tile coordinates, keeping the
+// east and south edges of the world inside the last column and row.
+func (g *grid) project(p orb.Point) (fx, fy float64) {
+	lon, lat := normalize(p.Lon(), p.Lat())
+	edge := math.Nextafter(g.n, 0)
+	fx = math.Max(0, math.Min(fracX(lon, g.n), edge))
+	fy = math.Max(0, math.Min(fracY(lat, g.n), edge))
+	return fx, fy
+}
+
+// bound returns the lon/lat bound of tile (x, y), as QuadKey.Bound does.
+func (g *grid) bound(x, y int) orb.Bound {
+	return tileBound(uint64(x), uint64(y), g.zoom)
+}
+
+// markBoundary records a tile touched by a point or an edge.
+func (g *grid) markBoundary(x, y int) {
+	if y < g.rowMin || y > g.rowMax {
+		return
+	}
+	c := cell{x, y}
+	if _, ok := g.cells[c]; !ok {
+		g.order = append(g.order, c)
+		g.progress.add()
+	}
+	g.cells[c] = false
+}
+
+// markInterior records a tile lying inside an areal geometry, unless an
+// edge already touches it.
+func (g *grid) markInterior(x, y int) {
+	if y < g.rowMin || y > g.rowMax {
+		return
+	}
+	c := cell{x, y}
+	if _, ok := g.cells[c]; !ok {
+		g.order = append(g.order, c)
+		g.cells[c] = true
+		g.progress.add()
+	}
+}
+
+// addSegment marks every tile the segment from a to b passes through,
+// including both tiles when it crosses exactly through a tile corner.
+func (g *grid) addSegment(a, b orb.Point) {
+	if g.canceled() {
+		return
+	}
+	ax, ay := g.project(a)
+	bx, by := g.project(b)
+
+	x, y := int(ax), int(ay)
+	ex, ey := int(bx), int(by)
+	g.markBoundary(x, y)
+
+	dx, dy := bx-ax, by-ay
+	stepX, tMaxX, tDeltaX := traversalStep(ax, dx)
+	stepY, tMaxY, tDeltaY := traversalStep(ay, dy)
+
+	// Each step moves one column or row closer to the end tile; the bound
+	// guards against float drift walking past it.
+	for steps := abs(ex-x) + abs(ey-y); steps > 0 && (x != ex || y != ey); steps-- {
+		switch {
+		case tMaxX < tMaxY:
+			x += stepX
+			tMaxX += tDeltaX
+		case tMaxY < tMaxX:
+			y += stepY
+			tMaxY += tDeltaY
+		default:
+			// Passing exactly through a corner touches both side tiles.
+			g.markBoundary(x+stepX, y)
+			g.markBoundary(x, y+stepY)
+			x += stepX
+			y += stepY
+			tMaxX += tDeltaX
+			tMaxY += tDeltaY
+			steps--
+		}
+		g.markBoundary(x, y)
+	}
+	// Float drift can stop the walk a step short; the end tile is covered
+	// regardless.
+	g.markBoundary(ex, ey)
+}
+
+// traversalStep returns, for one axis of a grid walk starting at v and moving
+// by d over t in [0, 1], the step direction, the t of the first tile border
+// crossing and the t between subsequent crossings.
+func traversalStep(v, d float64) (step int, tMax, tDelta float64) {
+	switch {
+	case d > 0:
+		return 1, (math.Floor(v) + 1 - v) / d, 1 / d
+	case d < 0:
+		return -1, (v - math.Floor(v)) / -d, 1 / -d
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
+
+// addPoint marks the tile containing p.
+func (g *grid) addPoint(p orb.Point) {
+	fx, fy := g.project(p)
+	g.markBoundary(int(fx), int(fy))
+}
+
+// addLineString marks every tile along the line.
+func (g *grid) addLineString(ls orb.LineString) {
+	if len(ls) == 1 {
+		g.addPoint(ls[0])
+	}
+	for i := 1; i < len(ls); i++ {
+		g.addSegment(ls[i-1], ls[i])
+	}
+}
+
+// addBound marks the tiles KeysInBound returns; tiles away from the edges of
+// that range lie fully inside the bound.
+func (g *grid) addBound(b orb.Bound) {
+	minX, minY, maxX, maxY := tileRange(b, g.zoom)
+	for x := minX; x <= maxX && !g.canceled(); x++ {
+		for y := max(minY, g.rowMin); y <= min(maxY, g.rowMax); y++ {
+			if x == minX || x == maxX || y == minY || y == maxY {
+				g.markBoundary(x, y)
+			} else {
+				g.markInterior(x, y)
+			}
+		}
+	}
+}
+
+// addCircle marks the tiles whose nearest point lies within radiusMeters of
+// center on the sphere.
+func (g *grid) addCircle(center orb.Point, radiusMeters float64) {
+	lon, lat := normalize(center.Lon(), center.Lat())
+	center = orb.Point{lon, lat}
+	n := 1 << g.zoom
+
+	// Latitude span of the circle; if it reaches a pole, every column does.
+	dLat := radToDeg(radiusMeters / orb.EarthRadius)
+	north, south := lat+dLat, lat-dLat
+	var west, east float64
+	if north >= 90 || south <= -90 {
+		west, east = -180, 180
+	} else {
+		dLon := radToDeg(math.Asin(math.Min(1, math.Sin(degToRad(dLat))/math.Cos(degToRad(lat)))))
+		west, east = lon-dLon, lon+dLon
+	}
+
+	// Columns are computed without clamping so the span can wrap.
+	first := int(math.Floor(fracX(west, g.n)))
+	last := int(math.Floor(fracX(east, g.n)))
+	columns := []int{}
+	for x := first; x <= last && len(columns) < n; x++ {
+		columns = append(columns, wrapX(x, n))
+	}
+
+	for y := max(toY(math.Min(north, 90), g.zoom), g.rowMin); y <= min(toY(math.Max(south, -90), g.zoom), g.rowMax) && !g.canceled(); y++ {
+		for _, x := range columns {
+			if distanceToBound(center, g.bound(x, y)) <= radiusMeters {
+				g.markBoundary(x, y)
+			}
+		}
+	}
+}
+
+// add dispatches on the geometry type.
+func (g *grid) add(geom orb.Geometry) {
+	switch geom := geom.(type) {
+	case orb.Point:
+		g.addPoint(geom)
+	case orb.MultiPoint:
+		for _, p := range geom {
+			g.addPoint(p)
+		}
+	case orb.LineString:
+		g.addLineString(geom)
+	case orb.MultiLineString:
+		for _, ls := range geom {
+			g.addLineString(ls)
+		}
+	case orb.Ring:
+		g.addPolygon(orb.Polygon{geom})
+	case orb.Polygon:
+		g.addPolygon(geom)
+	case orb.MultiPolygon:
+		for _, poly := range geom {
+			g.addPolygon(poly)
+		}
+	case orb.Bound:
+		g.addBound(geom)
+	case orb.Collection:
+		for _, member := range geom {
+			g.add(member)
+		}
+	}
+}
+
+// addRing marks the tiles along the ring's edges.
+func (g *grid) addRing(ring orb.Ring) {
+	for i := range ring {
+		g.addSegment(ring[i], ring[(i+1)%len(ring)])
+	}
+}
+
+// addPolygon marks the tiles along the polygon's rings, then fills the tiles
+// whose centers lie inside it. A tile that no edge touches is either entirely
+// inside or entirely outside, so testing its center is exact.
+func (g *grid) addPolygon(poly orb.Polygon) {
+	if len(poly) == 0 || len(poly[0]) == 0 {
+		return
+	}
+	for _, ring := range poly {
+		g.addRing(ring)
+	}
+
+	type edge struct{ ax, ay, bx, by float64 }
+	edges := []edge{}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, ring := range poly {
+		for i := range ring {
+			ax, ay := g.project(ring[i])
+			bx, by := g.project(ring[(i+1)%len(ring)])
+			edges = append(edges, edge{ax, ay, bx, by})
+			minY, maxY = math.Min(minY, ay), math.Max(maxY, ay)
+		}
+	}
+
+	crossings := []float64{}
+	for y := max(int(minY), g.rowMin); y <= min(int(maxY), g.rowMax) && !g.canceled(); y++ {
+		// Even-odd scanline through the row's tile centers handles holes.
+		cy := float64(y) + 0.5
+		crossings = crossings[:0]
+		for _, e := range edges {
+			if (e.ay <= cy) != (e.by <= cy) {
+				crossings = append(crossings, e.ax+(cy-e.ay)*(e.bx-e.ax)/(e.by-e.ay))
+			}
+		}
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			// Tiles whose center x+0.5 lies within the span.
+			first := int(math.Ceil(crossings[i] - 0.5))
+			last := int(math.Floor(crossings[i+1] - 0.5))
+			for x := first; x <= last; x++ {
+				g.markInterior(x, y)
+			}
+		}
+	}
+}
+
+// keys returns the covered tiles sorted in quadkey order.
+func (g *grid) keys() []QuadKey {
+	keys := make([]QuadKey, 0, len(g.cells))
+	for c := range g.cells {
+		keys = append(keys, FromXYZ(c.x, c.y, g.zoom))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// orderedKeys returns the covered tiles in the order they were first marked.
+func (g *grid) orderedKeys() []QuadKey {
+	keys := make([]QuadKey, 0, len(g.order))
+	for _, c := range g.order {
+		keys = append(keys, FromXYZ(c.x, c.y, g.zoom))
+	}
+	return keys
+}
+
+// sampledLength returns an upper bound, in meters, on the length of the
+// straight lon/lat path from a to b that CoverBuffered samples, which can be
+// far longer than the great-circle distance: along a parallel, or the long
+// way around the antimeridian. Every piece of the path is no longer than its
+// longitude span along the parallel nearest the equator plus its latitude
+// span along a meridian, so evenly spaced samples of the path lie no further
+// apart than their share of the bound.
+func sampledLength(a, b orb.Point) float64 {
+	lat := math.Min(math.Abs(a[1]), math.Abs(b[1]))
+	if (a[1] < 0) != (b[1] < 0) {
+		lat = 0
+	}
+	dLon := degToRad(math.Abs(b[0] - a[0]))
+	dLat := degToRad(math.Abs(b[1] - a[1]))
+	return orb.EarthRadius * (dLon*math.Cos(degToRad(lat)) + dLat)
+}
+
+// walkOutline calls segment for every edge of the geometry's lines, rings and
+// bounds, and for every point as a zero-length segment.
+func walkOutline(geom orb.Geometry, segment func(a, b orb.Point)) {
+	switch geom := geom.(type) {
+	case orb.Point:
+		segment(geom, geom)
+	case orb.MultiPoint:
+		for _, p := range geom {
+			segment(p, p)
+		}
+	case orb.LineString:
+		if len(geom) == 1 {
+			segment(geom[0], geom[0])
+		}
+		for i := 1; i < len(geom); i++ {
+			segment(geom[i-1], geom[i])
+		}
+	case orb.MultiLineString:
+		for _, ls := range geom {
+			walkOutline(ls, segment)
+		}
+	case orb.Ring:
+		for i := range geom {
+			segment(geom[i], geom[(i+1)%len(geom)])
+		}
+	case orb.Polygon:
+		for _, ring := range geom {
+			walkOutline(ring, segment)
+		}
+	case orb.MultiPolygon:
+		for _, poly := range geom {
+			walkOutline(poly, segment)
+		}
+	case orb.Bound:
+		walkOutline(geom.ToRing(), segment)
+	case orb.Collection:
+		for _, member := range geom {
+			walkOutline(member, segment)
+		}
+	}
+}
+
+// coverage returns the covered tiles split by classification.
+func (g *grid) coverage() Coverage {
+	c := Coverage{Interior: []QuadKey{}, Boundary: []QuadKey{}}
+	for cl, interior := range g.cells {
+		key := FromXYZ(cl.x, cl.y, g.zoom)
+		if interior {
+			c.Interior = append(c.Interior, key)
+		} else {
+			c.Boundary = append(c.Boundary, key)
+		}
+	}
+	slices.Sort(c.Interior)
+	slices.Sort(c.Boundary)
+	return c
+}
+
+// arealParts appends the polygons making up the areal parts of the geometry.
+func arealParts(geom orb.Geometry, parts []orb.Polygon) []orb.Polygon {
+	switch geom := geom.(type) {
+	case orb.Ring:
+		parts = append(parts, orb.Polygon{geom})
+	case orb.Polygon:
+		parts = append(parts, geom)
+	case orb.MultiPolygon:
+		parts = append(parts, geom...)
+	case orb.Bound:
+		parts = append(parts, geom.ToPolygon())
+	case orb.Collection:
+		for _, member := range geom {
+			parts = arealParts(member, parts)
+		}
+	}
+	return parts
+}
+
+// clipRing clips a ring to the rectangle using Sutherland-Hodgman. The
+// result may contain degenerate edges along the rectangle, which do not
+// affect its area.
+func clipRing(ring []vec, minX, minY, maxX, maxY float64) []vec {
+	inside := [4]func(v vec) bool{
+		func(v vec) bool { return v.x >= minX },
+		func(v vec) bool { return v.x <= maxX },
+		func(v vec) bool { return v.y >= minY },
+		func(v vec) bool { return v.y <= maxY },
+	}
+	intersect := [4]func(a, b vec) vec{
+		func(a, b vec) vec { return vec{minX, a.y + (b.y-a.y)*(minX-a.x)/(b.x-a.x)} },
+		func(a, b vec) vec { return vec{maxX, a.y + (b.y-a.y)*(maxX-a.x)/(b.x-a.x)} },
+		func(a, b vec) vec { return vec{a.x + (b.x-a.x)*(minY-a.y)/(b.y-a.y), minY} },
+		func(a, b vec) vec { return vec{a.x + (b.x-a.x)*(maxY-a.y)/(b.y-a.y), maxY} },
+	}
+
+	out := ring
+	for side := range inside {
+		in := out
+		out = make([]vec, 0, len(in)+4)
+		for i := range in {
+			cur, prev := in[i], in[(i+len(in)-1)%len(in)]
+			switch {
+			case inside[side](cur):
+				if !inside[side](prev) {
+					out = append(out, intersect[side](prev, cur))
+				}
+				out = append(out, cur)
+			case inside[side](prev):
+				out = append(out, intersect[side](prev, cur))
+			}
+		}
+	}
+	return out
+}
+
+// ringArea returns the signed shoelace area of a ring.
+func ringArea(ring []vec) float64 {
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
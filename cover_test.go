@@ -0,0 +1,771 @@
+package quadkey
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// segmentTouchesCell reports whether the segment a-b (tile coordinates)
+// intersects the closed unit square of tile (x, y), via Liang-Barsky clipping.
+func segmentTouchesCell(ax, ay, bx, by float64, x, y int) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := bx-ax, by-ay
+	for _, c := range [][2]float64{
+		{-dx, ax - float64(x)},
+		{dx, float64(x+1) - ax},
+		{-dy, ay - float64(y)},
+		{dy, float64(y+1) - ay},
+	} {
+		p, q := c[0], c[1]
+		if p == 0 {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// bruteForcePolygon tests every tile of the polygon's bound individually.
+func bruteForcePolygon(poly orb.Polygon, zoom int) map[QuadKey]bool {
+	g := newGrid(zoom)
+	want := map[QuadKey]bool{}
+	for _, k := range KeysInBound(poly.Bound(), zoom) {
+		x, y, _ := k.XYZ()
+		inside := false
+		touched := false
+		cx, cy := float64(x)+0.5, float64(y)+0.5
+		for _, ring := range poly {
+			for i := range ring {
+				ax, ay := g.project(ring[i])
+				bx, by := g.project(ring[(i+1)%len(ring)])
+				if segmentTouchesCell(ax, ay, bx, by, x, y) {
+					touched = true
+				}
+				if (ay <= cy) != (by <= cy) && cx < ax+(cy-ay)*(bx-ax)/(by-ay) {
+					inside = !inside
+				}
+			}
+		}
+		if touched || inside {
+			want[k] = true
+		}
+	}
+	return want
+}
+
+func assertKeySet(t *testing.T, name string, got []QuadKey, want map[QuadKey]bool) {
+	t.Helper()
+	seen := map[QuadKey]bool{}
+	for _, k := range got {
+		if seen[k] {
+			t.Fatalf("%s: duplicate key %q", name, k)
+		}
+		seen[k] = true
+		if !want[k] {
+			t.Fatalf("%s: unexpected key %q", name, k)
+		}
+	}
+	for k := range want {
+		if !seen[k] {
+			t.Fatalf("%s: missing key %q", name, k)
+		}
+	}
+}
+
+func assertSorted(t *testing.T, name string, keys []QuadKey) {
+	t.Helper()
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			t.Fatalf("%s: keys not sorted at %d: %q >= %q", name, i, keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestKeysInPolygonRectangleMatchesBound(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.51, 35.51}, Max: orb.Point{139.99, 35.99}}
+	poly := bound.ToPolygon()
+	zoom := 11
+
+	want := map[QuadKey]bool{}
+	for _, k := range KeysInBound(bound, zoom) {
+		want[k] = true
+	}
+	got := KeysInPolygon(poly, zoom)
+	assertKeySet(t, "rectangle", got, want)
+	assertSorted(t, "rectangle", got)
+}
+
+func TestKeysInPolygonDiagonal(t *testing.T) {
+	// A thin diagonal sliver covers far fewer tiles than its bound.
+	poly := orb.Polygon{{
+		{0, 0}, {10, 10}, {10.1, 10}, {0.1, 0}, {0, 0},
+	}}
+	zoom := 8
+
+	got := KeysInPolygon(poly, zoom)
+	assertKeySet(t, "diagonal", got, bruteForcePolygon(poly, zoom))
+	if b := KeysInBound(poly.Bound(), zoom); !(len(got) < len(b)/3) {
+		t.Fatalf("expected sliver to cover far fewer tiles than its bound: %d vs %d", len(got), len(b))
+	}
+}
+
+func TestKeysInPolygonRespectsHoles(t *testing.T) {
+	poly := orb.Polygon{
+		{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}},
+		{{-5, -5}, {-5, 5}, {5, 5}, {5, -5}, {-5, -5}},
+	}
+	zoom := 7
+
+	got := KeysInPolygon(poly, zoom)
+	assertKeySet(t, "hole", got, bruteForcePolygon(poly, zoom))
+
+	hole := FromLonLat(0, 0, zoom)
+	for _, k := range got {
+		if k == hole {
+			t.Fatalf("tile %q inside the hole should be excluded", k)
+		}
+	}
+}
+
+func TestKeysInPolygonConcave(t *testing.T) {
+	// A "U" shape: the notch at the top must stay uncovered.
+	poly := orb.Polygon{{
+		{0, 0}, {30, 0}, {30, 30}, {20, 30}, {20, 10}, {10, 10}, {10, 30}, {0, 30}, {0, 0},
+	}}
+	zoom := 6
+
+	got := KeysInPolygon(poly, zoom)
+	assertKeySet(t, "concave", got, bruteForcePolygon(poly, zoom))
+	notch := FromLonLat(15, 25, zoom)
+	for _, k := range got {
+		if k == notch {
+			t.Fatalf("tile %q in the notch should be excluded", k)
+		}
+	}
+}
+
+func TestKeysInPolygonSmallerThanTile(t *testing.T) {
+	poly := orb.Polygon{{
+		{139.70, 35.60}, {139.71, 35.60}, {139.71, 35.61}, {139.70, 35.60},
+	}}
+	got := KeysInPolygon(poly, 5)
+	if len(got) != 1 || got[0] != FromLonLat(139.705, 35.605, 5) {
+		t.Fatalf("expected single containing tile, got %v", got)
+	}
+}
+
+func TestKeysInPolygonEmpty(t *testing.T) {
+	if got := KeysInPolygon(orb.Polygon{}, 5); len(got) != 0 {
+		t.Fatalf("expected no keys for empty polygon, got %v", got)
+	}
+	poly := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}.ToPolygon()
+	if got := KeysInPolygon(poly, 0); len(got) != 0 {
+		t.Fatalf("expected no keys at zoom 0, got %v", got)
+	}
+}
+
+func TestCoverPoints(t *testing.T) {
+	p := orb.Point{139.767125, 35.681236}
+	got := Cover(p, 8)
+	if len(got) != 1 || got[0] != FromPoint(p, 8) {
+		t.Fatalf("point cover: got %v", got)
+	}
+
+	mp := orb.MultiPoint{p, p, {0, 0}}
+	got = Cover(mp, 8)
+	assertKeySet(t, "multipoint", got, map[QuadKey]bool{
+		FromPoint(p, 8):           true,
+		FromPoint(orb.Point{}, 8): true,
+	})
+}
+
+func TestCoverLineString(t *testing.T) {
+	// A horizontal line across three tiles at zoom 2.
+	ls := orb.LineString{{-170, 10}, {10, 10}}
+	got := Cover(ls, 2)
+	assertKeySet(t, "line", got, map[QuadKey]bool{
+		FromXYZ(0, 1, 2): true,
+		FromXYZ(1, 1, 2): true,
+		FromXYZ(2, 1, 2): true,
+	})
+
+	mls := orb.MultiLineString{ls, {{100, -10}, {100, -11}}}
+	got = Cover(mls, 2)
+	if len(got) != 4 {
+		t.Fatalf("multilinestring: got %v", got)
+	}
+}
+
+func TestCoverPolygonTypes(t *testing.T) {
+	poly := orb.Polygon{{{0, 0}, {10, 10}, {10.1, 10}, {0.1, 0}, {0, 0}}}
+	want := KeysInPolygon(poly, 8)
+
+	for name, g := range map[string]orb.Geometry{
+		"polygon":      poly,
+		"ring":         poly[0],
+		"multipolygon": orb.MultiPolygon{poly, poly},
+		"collection":   orb.Collection{poly, orb.Point{5, 5}},
+	} {
+		got := Cover(g, 8)
+		wantSet := map[QuadKey]bool{}
+		for _, k := range want {
+			wantSet[k] = true
+		}
+		assertKeySet(t, name, got, wantSet)
+		assertSorted(t, name, got)
+	}
+}
+
+func TestCoverBoundMatchesKeysInBound(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	want := map[QuadKey]bool{}
+	for _, k := range KeysInBound(bound, 10) {
+		want[k] = true
+	}
+	assertKeySet(t, "bound", Cover(bound, 10), want)
+}
+
+func TestCoverUnionDeduplicates(t *testing.T) {
+	a := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+	b := orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{15, 15}}
+	got := Cover(orb.Collection{a, b}, 6)
+
+	want := map[QuadKey]bool{}
+	for _, k := range append(KeysInBound(a, 6), KeysInBound(b, 6)...) {
+		want[k] = true
+	}
+	assertKeySet(t, "union", got, want)
+}
+
+func TestCoverEmpty(t *testing.T) {
+	if got := Cover(nil, 5); len(got) != 0 {
+		t.Fatalf("expected no keys for nil geometry, got %v", got)
+	}
+	if got := Cover(orb.Point{}, 0); len(got) != 0 {
+		t.Fatalf("expected no keys at zoom 0, got %v", got)
+	}
+}
+
+func TestCoverWithOptionsWorkersMatchSerial(t *testing.T) {
+	hole := orb.Ring{{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}}
+	poly := orb.Polygon{{{0, 0}, {12, 1}, {8, 5}, {11, 11}, {1, 9}, {0, 0}}, hole}
+	for name, g := range map[string]orb.Geometry{
+		"polygon":    poly,
+		"line":       orb.LineString{{-20, -10}, {15, 30}, {35, 5}},
+		"bound":      orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}},
+		"points":     orb.MultiPoint{{1, 1}, {-50, 60}, {120, -30}},
+		"collection": orb.Collection{poly, orb.Point{-50, 60}},
+	} {
+		want := map[QuadKey]bool{}
+		for _, k := range Cover(g, 10) {
+			want[k] = true
+		}
+		for _, workers := range []int{2, 3, 8, 1000} {
+			got := CoverWithOptions(g, 10, CoverOptions{Workers: workers})
+			assertKeySet(t, name, got, want)
+			assertSorted(t, name, got)
+		}
+	}
+}
+
+func TestCoverWithOptionsEmpty(t *testing.T) {
+	opts := CoverOptions{Workers: 4}
+	if got := CoverWithOptions(nil, 5, opts); len(got) != 0 {
+		t.Fatalf("expected no keys for nil geometry, got %v", got)
+	}
+	if got := CoverWithOptions(orb.Collection{}, 5, opts); len(got) != 0 {
+		t.Fatalf("expected no keys for empty collection, got %v", got)
+	}
+}
+
+func TestCoverCtx(t *testing.T) {
+	poly := orb.Polygon{{{0, 0}, {12, 1}, {8, 5}, {11, 11}, {1, 9}, {0, 0}}}
+	want := Cover(poly, 10)
+	for _, workers := range []int{0, 4} {
+		got, err := CoverCtx(context.Background(), poly, 10, CoverOptions{Workers: workers})
+		if err != nil {
+			t.Fatalf("workers %d: unexpected error: %v", workers, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("workers %d: keys differ from Cover", workers)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, workers := range []int{0, 4} {
+		got, err := CoverCtx(ctx, poly, 10, CoverOptions{Workers: workers})
+		if !errors.Is(err, context.Canceled) || got != nil {
+			t.Fatalf("workers %d canceled: got %d keys, err %v", workers, len(got), err)
+		}
+	}
+}
+
+func TestCoverProgress(t *testing.T) {
+	poly := orb.Polygon{{{0, 0}, {12, 1}, {8, 5}, {11, 11}, {1, 9}, {0, 0}}}
+	for _, workers := range []int{0, 4} {
+		var calls, last, lastEstimate int64
+		keys := CoverWithOptions(poly, 12, CoverOptions{
+			Workers: workers,
+			Progress: func(emitted, estimated int64) {
+				if emitted < last {
+					t.Errorf("workers %d: emitted went back from %d to %d", workers, last, emitted)
+				}
+				if estimated < emitted {
+					t.Errorf("workers %d: estimate %d below emitted %d", workers, estimated, emitted)
+				}
+				calls++
+				last, lastEstimate = emitted, estimated
+			},
+		})
+		if calls < 2 {
+			t.Fatalf("workers %d: expected periodic reports, got %d calls", workers, calls)
+		}
+		if last != int64(len(keys)) {
+			t.Fatalf("workers %d: final emitted %d, want %d", workers, last, len(keys))
+		}
+		if lastEstimate < last {
+			t.Fatalf("workers %d: final estimate %d below %d", workers, lastEstimate, last)
+		}
+	}
+}
+
+func TestGridStopsWhenDone(t *testing.T) {
+	done := make(chan struct{})
+	close(done)
+	g := newGrid(12)
+	g.done = done
+	g.add(orb.Collection{
+		orb.Polygon{{{0, 0}, {12, 1}, {11, 11}, {0, 0}}},
+		orb.LineString{{-20, -10}, {15, 30}},
+		orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}},
+	})
+	g.addCircle(orb.Point{0, 0}, 100000)
+	if len(g.cells) != 0 {
+		t.Fatalf("expected no cells after done, got %d", len(g.cells))
+	}
+}
+
+func TestCoverFeature(t *testing.T) {
+	poly := orb.Polygon{{{0, 0}, {10, 10}, {10.1, 10}, {0.1, 0}, {0, 0}}}
+	feature := geojson.NewFeature(poly)
+
+	got := CoverFeature(feature, 8)
+	want := Cover(poly, 8)
+	assertSameKeys(t, "feature", got, want)
+
+	if got := CoverFeature(nil, 8); len(got) != 0 {
+		t.Fatalf("expected no keys for nil feature, got %v", got)
+	}
+}
+
+func TestCoverFeatureCollection(t *testing.T) {
+	a := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+	b := orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{15, 15}}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(a.ToPolygon()))
+	fc.Append(geojson.NewFeature(b.ToPolygon()))
+	fc.Append(geojson.NewFeature(orb.Point{100, 0}))
+
+	got := CoverFeatureCollection(fc, 6)
+	assertSameKeys(t, "collection", got, Cover(orb.Collection{a.ToPolygon(), b.ToPolygon(), orb.Point{100, 0}}, 6))
+	assertSorted(t, "collection", got)
+
+	each := CoverEachFeature(fc, 6)
+	assertEqualInt(t, "per-feature covers", len(each), 3)
+	assertSameKeys(t, "feature 0", each[0], Cover(a.ToPolygon(), 6))
+	assertSameKeys(t, "feature 1", each[1], Cover(b.ToPolygon(), 6))
+	assertSameKeys(t, "feature 2", each[2], []QuadKey{FromPoint(orb.Point{100, 0}, 6)})
+
+	if got := CoverFeatureCollection(nil, 6); len(got) != 0 {
+		t.Fatalf("expected no keys for nil collection, got %v", got)
+	}
+	if got := CoverEachFeature(nil, 6); len(got) != 0 {
+		t.Fatalf("expected no covers for nil collection, got %v", got)
+	}
+}
+
+func TestKeysAlongLineOrder(t *testing.T) {
+	// West to east, then back south-west.
+	ls := orb.LineString{{-170, 10}, {100, 10}, {100, -10}}
+	got := KeysAlongLine(ls, 2)
+	want := []QuadKey{
+		FromXYZ(0, 1, 2), FromXYZ(1, 1, 2), FromXYZ(2, 1, 2), FromXYZ(3, 1, 2),
+		FromXYZ(3, 2, 2),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("line: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("line[%d]: got %q, want %q (all=%v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestKeysAlongLineDiagonalHasNoGaps(t *testing.T) {
+	ls := orb.LineString{{-10, -10}, {10, 10}}
+	zoom := 9
+	got := KeysAlongLine(ls, zoom)
+
+	// Consecutive tiles share an edge or, at exact corner crossings, a
+	// corner whose side tiles are also present.
+	seen := map[QuadKey]bool{}
+	for _, k := range got {
+		seen[k] = true
+	}
+	for i := 1; i < len(got); i++ {
+		x0, y0, _ := got[i-1].XYZ()
+		x1, y1, _ := got[i].XYZ()
+		if abs(x1-x0)+abs(y1-y0) == 1 {
+			continue
+		}
+		if abs(x1-x0) == 1 && abs(y1-y0) == 1 && seen[FromXYZ(x1, y0, zoom)] && seen[FromXYZ(x0, y1, zoom)] {
+			continue
+		}
+		t.Fatalf("gap between %q and %q", got[i-1], got[i])
+	}
+
+	// Brute force: the walk finds exactly the tiles the segment touches.
+	g := newGrid(zoom)
+	ax, ay := g.project(ls[0])
+	bx, by := g.project(ls[1])
+	want := map[QuadKey]bool{}
+	for _, k := range KeysInBound(ls.Bound(), zoom) {
+		x, y, _ := k.XYZ()
+		if segmentTouchesCell(ax, ay, bx, by, x, y) {
+			want[k] = true
+		}
+	}
+	assertKeySet(t, "diagonal", got, want)
+}
+
+func TestKeysAlongLineExactCorner(t *testing.T) {
+	// The diagonal of the south-east quadrant passes exactly through the
+	// grid corners, so both tiles beside each corner are included as well.
+	got := KeysAlongLine(orb.LineString{{0, 0}, {180, -90}}, 3)
+	assertEqualInt(t, "diagonal tiles", len(got), 4+2*3)
+	for i := 4; i < 8; i++ {
+		if !slices.Contains(got, FromXYZ(i, i, 3)) {
+			t.Fatalf("missing diagonal tile (%d,%d)", i, i)
+		}
+	}
+}
+
+func TestKeysAlongLineDegenerate(t *testing.T) {
+	got := KeysAlongLine(orb.LineString{{139.7, 35.6}}, 10)
+	if len(got) != 1 || got[0] != FromLonLat(139.7, 35.6, 10) {
+		t.Fatalf("single point line: got %v", got)
+	}
+	if got := KeysAlongLine(orb.LineString{}, 10); len(got) != 0 {
+		t.Fatalf("expected no keys for empty line, got %v", got)
+	}
+}
+
+func TestKeysInCircleMatchesBruteForce(t *testing.T) {
+	center := orb.Point{139.767125, 35.681236}
+	radius := 5000.0
+	zoom := 13
+
+	got := KeysInCircle(center, radius, zoom)
+	assertSorted(t, "circle", got)
+
+	// Compare against every tile in a generous window around the center.
+	cx, cy, _ := FromPoint(center, zoom).XYZ()
+	want := map[QuadKey]bool{}
+	for x := cx - 20; x <= cx+20; x++ {
+		for y := cy - 20; y <= cy+20; y++ {
+			k := FromXYZ(x, y, zoom)
+			if distanceToBound(center, k.Bound()) <= radius {
+				want[k] = true
+			}
+		}
+	}
+	assertKeySet(t, "circle", got, want)
+
+	// The circle is much tighter than its bounding square.
+	square := KeysInBound(orb.Bound{
+		Min: orb.Point{center.Lon() - 0.056, center.Lat() - 0.045},
+		Max: orb.Point{center.Lon() + 0.056, center.Lat() + 0.045},
+	}, zoom)
+	if !(len(got) < len(square)) {
+		t.Fatalf("expected fewer tiles than the bounding square: %d vs %d", len(got), len(square))
+	}
+}
+
+func TestKeysInCircleZeroRadius(t *testing.T) {
+	p := orb.Point{10, 10}
+	got := KeysInCircle(p, 0, 10)
+	if len(got) != 1 || got[0] != FromPoint(p, 10) {
+		t.Fatalf("zero radius: got %v", got)
+	}
+}
+
+func TestKeysInCircleWrapsAntimeridian(t *testing.T) {
+	got := KeysInCircle(orb.Point{179.99, 0}, 50000, 8)
+	west, east := false, false
+	for _, k := range got {
+		x, _, _ := k.XYZ()
+		west = west || x == 0
+		east = east || x == 255
+	}
+	if !west || !east {
+		t.Fatalf("expected tiles on both sides of the antimeridian, got %v", got)
+	}
+}
+
+func TestKeysInCircleCoversPole(t *testing.T) {
+	// A circle over the pole reaches the top row on the far side as well.
+	got := KeysInCircle(orb.Point{0, 84}, 1500000, 3)
+	top := 0
+	for _, k := range got {
+		if _, y, _ := k.XYZ(); y == 0 {
+			top++
+		}
+	}
+	assertEqualInt(t, "top row tiles", top, 8)
+}
+
+func TestKeysInCircleInvalid(t *testing.T) {
+	if got := KeysInCircle(orb.Point{}, -1, 5); len(got) != 0 {
+		t.Fatalf("expected no keys for negative radius, got %v", got)
+	}
+	if got := KeysInCircle(orb.Point{}, 10, 0); len(got) != 0 {
+		t.Fatalf("expected no keys at zoom 0, got %v", got)
+	}
+}
+
+func TestCoverBufferedZeroIsCover(t *testing.T) {
+	ls := orb.LineString{{139.70, 35.60}, {139.80, 35.70}}
+	assertSameKeys(t, "zero buffer", CoverBuffered(ls, 0, 14), Cover(ls, 14))
+}
+
+func TestCoverBufferedPoint(t *testing.T) {
+	p := orb.Point{139.767125, 35.681236}
+	got := CoverBuffered(p, 2000, 14)
+	assertSorted(t, "buffered point", got)
+
+	inner := KeysInCircle(p, 2000, 14)
+	outer := map[QuadKey]bool{}
+	for _, k := range KeysInCircle(p, 2000*1.125, 14) {
+		outer[k] = true
+	}
+	for _, k := range inner {
+		if !slices.Contains(got, k) {
+			t.Fatalf("missing tile %q within the buffer", k)
+		}
+	}
+	for _, k := range got {
+		if !outer[k] {
+			t.Fatalf("tile %q lies too far outside the buffer", k)
+		}
+	}
+}
+
+func TestCoverBufferedLine(t *testing.T) {
+	ls := orb.LineString{{139.70, 35.60}, {139.80, 35.70}}
+	buffer := 1000.0
+	zoom := 14
+	got := CoverBuffered(ls, buffer, zoom)
+
+	set := map[QuadKey]bool{}
+	for _, k := range got {
+		set[k] = true
+	}
+	for _, k := range Cover(ls, zoom) {
+		if !set[k] {
+			t.Fatalf("buffered cover is missing line tile %q", k)
+		}
+	}
+
+	// Distance from a tile to the line, by dense sampling.
+	distance := func(k QuadKey) float64 {
+		d := math.Inf(1)
+		for i := 0; i <= 2000; i++ {
+			f := float64(i) / 2000
+			p := orb.Point{ls[0][0] + (ls[1][0]-ls[0][0])*f, ls[0][1] + (ls[1][1]-ls[0][1])*f}
+			d = math.Min(d, distanceToBound(p, k.Bound()))
+		}
+		return d
+	}
+
+	for _, k := range KeysInBound(orb.Bound{Min: orb.Point{139.65, 35.55}, Max: orb.Point{139.85, 35.75}}, zoom) {
+		d := distance(k)
+		if d < buffer*0.99 && !set[k] {
+			t.Fatalf("tile %q at %.0fm is within the buffer but missing", k, d)
+		}
+		if d > buffer*1.13 && set[k] {
+			t.Fatalf("tile %q at %.0fm is included but far outside the buffer", k, d)
+		}
+	}
+}
+
+func TestCoverBufferedLongParallel(t *testing.T) {
+	// Along 60°N the sampled lon/lat path is far longer than the
+	// great-circle distance, and across the long way around it is longer
+	// still; samples must follow the path.
+	buffer := 2000.0
+	zoom := 15
+	offset := 0.99 * buffer / orb.EarthRadius * 180 / math.Pi
+	for _, ls := range []orb.LineString{{{0, 60}, {120, 60}}, {{-179, 60}, {179, 60}}} {
+		set := map[QuadKey]bool{}
+		for _, k := range CoverBuffered(ls, buffer, zoom) {
+			set[k] = true
+		}
+		for lon := ls[0][0]; lon <= ls[1][0]; lon += 0.01 {
+			for _, lat := range []float64{60 + offset, 60 - offset} {
+				if k := FromLonLat(lon, lat, zoom); !set[k] {
+					t.Fatalf("%v: tile %q at (%v, %v) is within the buffer but missing", ls, k, lon, lat)
+				}
+			}
+		}
+	}
+}
+
+func TestCoverBufferedPolygonKeepsInterior(t *testing.T) {
+	poly := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}.ToPolygon()
+	got := CoverBuffered(poly, 5000, 10)
+	for _, k := range Cover(poly, 10) {
+		if !slices.Contains(got, k) {
+			t.Fatalf("missing polygon tile %q", k)
+		}
+	}
+	if !(len(got) > len(Cover(poly, 10))) {
+		t.Fatalf("expected buffered cover to grow beyond the polygon")
+	}
+}
+
+func TestCoverBufferedInvalid(t *testing.T) {
+	if got := CoverBuffered(orb.Point{}, -1, 5); len(got) != 0 {
+		t.Fatalf("expected no keys for negative buffer, got %v", got)
+	}
+}
+
+func TestCoverClassifiedPolygon(t *testing.T) {
+	poly := orb.Polygon{
+		{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}},
+		{{-5, -5}, {-5, 5}, {5, 5}, {5, -5}, {-5, -5}},
+	}
+	zoom := 7
+	c := CoverClassified(poly, zoom)
+	assertSorted(t, "interior", c.Interior)
+	assertSorted(t, "boundary", c.Boundary)
+	assertSameKeys(t, "all keys", c.Keys(), Cover(poly, zoom))
+
+	s := newShape(poly)
+	for _, k := range c.Interior {
+		x, y, z := k.XYZ()
+		if r := s.relate(x, y, z); r != tileInterior {
+			t.Fatalf("interior tile %q relates as %d", k, r)
+		}
+	}
+	for _, k := range c.Boundary {
+		x, y, z := k.XYZ()
+		if r := s.relate(x, y, z); r != tileBoundary {
+			t.Fatalf("boundary tile %q relates as %d", k, r)
+		}
+	}
+	if len(c.Interior) == 0 || len(c.Boundary) == 0 {
+		t.Fatalf("expected both interior and boundary tiles: %d / %d", len(c.Interior), len(c.Boundary))
+	}
+}
+
+func TestCoverClassifiedBound(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+	c := CoverClassified(bound, 6)
+	assertSameKeys(t, "all keys", c.Keys(), KeysInBound(bound, 6))
+	for _, k := range c.Interior {
+		if !bound.Contains(k.Bound().Min) || !bound.Contains(k.Bound().Max) {
+			t.Fatalf("interior tile %q is not inside the bound", k)
+		}
+	}
+}
+
+func TestCoverClassifiedLinesHaveNoInterior(t *testing.T) {
+	c := CoverClassified(orb.LineString{{0, 0}, {10, 10}}, 8)
+	if len(c.Interior) != 0 || len(c.Boundary) == 0 {
+		t.Fatalf("line coverage: interior=%d boundary=%d", len(c.Interior), len(c.Boundary))
+	}
+}
+
+func TestCoverWeightedFractions(t *testing.T) {
+	// A polygon covering exactly the west half of tile (2, 1) at zoom 2,
+	// plus all of tile (1, 1).
+	west := FromXYZ(1, 1, 2).Bound()
+	mid := FromXYZ(2, 1, 2).Bound()
+	half := (mid.Left() + mid.Right()) / 2
+	poly := orb.Bound{Min: orb.Point{west.Left(), mid.Bottom() + 1e-9}, Max: orb.Point{half, mid.Top() - 1e-9}}.ToPolygon()
+
+	got := CoverWeighted(poly, 2)
+	weights := map[QuadKey]float64{}
+	for _, w := range got {
+		weights[w.Key] = w.Fraction
+	}
+	assertNear(t, "full tile", weights[FromXYZ(1, 1, 2)], 1, 1e-6)
+	assertNear(t, "half tile", weights[FromXYZ(2, 1, 2)], 0.5, 1e-6)
+	assertEqualInt(t, "weighted keys", len(got), 2)
+}
+
+func TestCoverWeightedSumsToArea(t *testing.T) {
+	poly := orb.Polygon{
+		{{0, 0}, {10, 1}, {8, 9}, {1, 7}, {0, 0}},
+		{{3, 3}, {4, 5}, {6, 4}, {3, 3}},
+	}
+	zoom := 8
+	got := CoverWeighted(poly, zoom)
+
+	// The weights add up to the polygon's area in tile units.
+	g := newGrid(zoom)
+	area := 0.0
+	for i, ring := range poly {
+		r := make([]vec, len(ring))
+		for j, p := range ring {
+			r[j].x, r[j].y = g.project(p)
+		}
+		a := math.Abs(ringArea(r))
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	total := 0.0
+	for _, w := range got {
+		if w.Fraction <= 0 || w.Fraction > 1 {
+			t.Fatalf("fraction out of range for %q: %f", w.Key, w.Fraction)
+		}
+		total += w.Fraction
+	}
+	assertNear(t, "total area", total, area, 1e-6)
+
+	// The hole's center tile is excluded or partial.
+	for _, w := range got {
+		if w.Key == FromLonLat(4.3, 4.1, zoom) && w.Fraction == 1 {
+			t.Fatalf("tile inside the hole reported as fully covered")
+		}
+	}
+}
+
+func TestCoverWeightedIgnoresLines(t *testing.T) {
+	if got := CoverWeighted(orb.LineString{{0, 0}, {10, 10}}, 8); len(got) != 0 {
+		t.Fatalf("expected no weighted keys for a line, got %v", got)
+	}
+}
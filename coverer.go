@@ -0,0 +1,336 @@
+package quadkey
+
+import (
+	"iter"
+	"math"
+	"slices"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// struct RegionCoverer
+// --------------------------
+
+// RegionCoverer computes mixed-zoom coverings: coarse tiles for the interior
+// of a geometry and progressively finer tiles along its boundary, in the
+// spirit of the S2 region coverer.
+type RegionCoverer struct {
+	// MinZoom is the coarsest zoom used; values below 1 are treated as 1.
+	MinZoom int
+	// MaxZoom is the finest zoom used; boundary tiles are never refined
+	// beyond it. Values below MinZoom are treated as MinZoom, and both are
+	// clamped to the package's MaxZoom.
+	MaxZoom int
+	// MaxCells caps the size of the covering. Boundary tiles are refined
+	// coarsest first for as long as the cap allows; 0 means no cap. The
+	// covering at MinZoom is always returned in full, even if it is larger.
+	MaxCells int
+}
+
+// Covering returns a set of keys, sorted in quadkey order, whose union covers
+// the geometry. Keys never overlap each other. Interior tiles are kept at the
+// coarsest zoom that fits entirely inside an areal geometry.
+func (rc RegionCoverer) Covering(g orb.Geometry) []QuadKey {
+	minZoom := min(max(rc.MinZoom, 1), MaxZoom)
+	maxZoom := min(max(rc.MaxZoom, minZoom), MaxZoom)
+
+	s := newShape(g)
+	result := []QuadKey{}
+	candidates := []QuadKey{}
+	gr := newGrid(minZoom)
+	gr.add(g)
+	for _, key := range gr.keys() {
+		x, y, z := key.XYZ()
+		switch s.relate(x, y, z) {
+		case tileInterior:
+			result = append(result, key)
+		case tileBoundary:
+			candidates = append(candidates, key)
+		}
+	}
+
+	// Refine level by level so the coarsest boundary tiles are split first.
+	for z := minZoom; z < maxZoom && len(candidates) > 0; z++ {
+		next := []QuadKey{}
+		for i, key := range candidates {
+			children := []QuadKey{}
+			interior := []QuadKey{}
+			for _, child := range key.Children() {
+				x, y, cz := child.XYZ()
+				switch s.relate(x, y, cz) {
+				case tileInterior:
+					interior = append(interior, child)
+				case tileBoundary:
+					children = append(children, child)
+				}
+			}
+
+			// Splitting replaces one tile with its intersecting children.
+			pending := len(candidates) - i - 1
+			total := len(result) + len(next) + pending + len(children) + len(interior)
+			if rc.MaxCells > 0 && total > rc.MaxCells {
+				result = append(result, key)
+				continue
+			}
+			result = append(result, interior...)
+			next = append(next, children...)
+		}
+		candidates = next
+	}
+
+	result = append(result, candidates...)
+	slices.Sort(result)
+	return result
+}
+
+// CoverPyramid returns the covering of the geometry at every zoom from
+// minZoom to maxZoom, indexed by zoom-minZoom, as IterCoverPyramid yields
+// them. minZoom values below 1 are treated as 1 and maxZoom values beyond
+// MaxZoom as MaxZoom; an empty range returns no levels.
+func CoverPyramid(g orb.Geometry, minZoom, maxZoom int) [][]QuadKey {
+	levels := [][]QuadKey{}
+	for _, keys := range IterCoverPyramid(g, minZoom, maxZoom) {
+		levels = append(levels, keys)
+	}
+	return levels
+}
+
+// IterCoverPyramid yields the zoom and the sorted covering keys of the
+// geometry for every zoom from minZoom to maxZoom, coarsest first. The first
+// level is Cover at minZoom; each deeper level is derived from the previous
+// one by testing only the children of its tiles, and children of interior
+// tiles are kept without testing. Every key's parent is therefore in the
+// level before it. Each boundary tile keeps the points and edges touching it,
+// so its children are tested against those alone rather than the whole
+// geometry. Beyond minZoom a tile whose border merely touches the
+// geometry counts as covered, so deeper levels may hold a few more tiles than
+// Cover at the same zoom. Zooms are clamped as CoverPyramid describes.
+func IterCoverPyramid(g orb.Geometry, minZoom, maxZoom int) iter.Seq2[int, []QuadKey] {
+	return func(yield func(int, []QuadKey) bool) {
+		first := max(minZoom, 1)
+		last := min(maxZoom, MaxZoom)
+		if g == nil || last < first {
+			return
+		}
+
+		gr := newGrid(first)
+		gr.add(g)
+		keys := gr.keys()
+		s := newShape(g)
+		interior := make([]bool, len(keys))
+		touching := make([]shapePart, len(keys))
+		for i, key := range keys {
+			x, y, z := key.XYZ()
+			if interior[i] = gr.cells[cell{x, y}]; !interior[i] {
+				_, touching[i] = s.relateWithin(x, y, z, shapePart{s.points, s.edges})
+			}
+		}
+
+		for z := first; ; z++ {
+			if !yield(z, keys) || z == last {
+				return
+			}
+
+			// Children of sorted parents, in digit order, stay sorted.
+			next := make([]QuadKey, 0, 2*len(keys))
+			nextInterior := make([]bool, 0, 2*len(keys))
+			nextTouching := make([]shapePart, 0, 2*len(keys))
+			for i, key := range keys {
+				for _, child := range key.Children() {
+					rel, part := tileInterior, shapePart{}
+					if !interior[i] {
+						x, y, cz := child.XYZ()
+						rel, part = s.relateWithin(x, y, cz, touching[i])
+					}
+					if rel != tileDisjoint {
+						next = append(next, child)
+						nextInterior = append(nextInterior, rel == tileInterior)
+						nextTouching = append(nextTouching, part)
+					}
+				}
+			}
+			keys, interior, touching = next, nextInterior, nextTouching
+		}
+	}
+}
+
+// --------------------------
+// struct shape
+// --------------------------
+
+type tileRelation int
+
+const (
+	tileDisjoint tileRelation = iota
+	tileBoundary
+	tileInterior
+)
+
+type vec struct {
+	x, y float64
+}
+
+type edge struct {
+	a, b vec
+}
+
+// shape is a geometry projected to world tile space, where the whole map spans
+// [0, 1] × [0, 1] and tile (x, y) at zoom z spans [x, x+1] × [y, y+1] / 2^z.
+type shape struct {
+	points []vec
+	// edges holds every line segment and polygon ring edge.
+	edges []edge
+	// polygons holds the ring edges of each areal part, for containment.
+	polygons [][]edge
+}
+
+func newShape(g orb.Geometry) *shape {
+	s := &shape{}
+	s.add(g)
+	return s
+}
+
+func projectWorld(p orb.Point) vec {
+	lon, lat := normalize(p.Lon(), p.Lat())
+	return vec{
+		math.Max(0, math.Min(fracX(lon, 1), 1)),
+		math.Max(0, math.Min(fracY(lat, 1), 1)),
+	}
+}
+
+func (s *shape) add(g orb.Geometry) {
+	switch g := g.(type) {
+	case orb.Point:
+		s.points = append(s.points, projectWorld(g))
+	case orb.MultiPoint:
+		for _, p := range g {
+			s.add(p)
+		}
+	case orb.LineString:
+		if len(g) == 1 {
+			s.add(g[0])
+		}
+		for i := 1; i < len(g); i++ {
+			s.edges = append(s.edges, edge{projectWorld(g[i-1]), projectWorld(g[i])})
+		}
+	case orb.MultiLineString:
+		for _, ls := range g {
+			s.add(ls)
+		}
+	case orb.Ring:
+		s.add(orb.Polygon{g})
+	case orb.Polygon:
+		edges := []edge{}
+		for _, ring := range g {
+			for i := range ring {
+				edges = append(edges, edge{projectWorld(ring[i]), projectWorld(ring[(i+1)%len(ring)])})
+			}
+		}
+		s.edges = append(s.edges, edges...)
+		s.polygons = append(s.polygons, edges)
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			s.add(poly)
+		}
+	case orb.Bound:
+		s.add(g.ToPolygon())
+	case orb.Collection:
+		for _, member := range g {
+			s.add(member)
+		}
+	}
+}
+
+// shapePart is a subset of a shape's points and edges, such as those
+// touching one tile.
+type shapePart struct {
+	points []vec
+	edges  []edge
+}
+
+// relate classifies tile (x, y, z) against the shape: touched by a point or
+// an edge (boundary), lying entirely inside an areal part (interior), or
+// neither (disjoint).
+func (s *shape) relate(x, y, z int) tileRelation {
+	rel, _ := s.relateWithin(x, y, z, shapePart{s.points, s.edges})
+	return rel
+}
+
+// relateWithin is relate testing only the points and edges of part, and
+// returns those touching the tile. A tile lies inside its parent, so the
+// part touching the parent gives the same result as the whole shape.
+func (s *shape) relateWithin(x, y, z int, part shapePart) (tileRelation, shapePart) {
+	n := math.Exp2(float64(z))
+	minX, minY := float64(x)/n, float64(y)/n
+	maxX, maxY := float64(x+1)/n, float64(y+1)/n
+
+	touching := shapePart{}
+	for _, p := range part.points {
+		if p.x >= minX && p.x <= maxX && p.y >= minY && p.y <= maxY {
+			touching.points = append(touching.points, p)
+		}
+	}
+	for _, e := range part.edges {
+		if segmentTouchesRect(e, minX, minY, maxX, maxY) {
+			touching.edges = append(touching.edges, e)
+		}
+	}
+	if len(touching.points) > 0 || len(touching.edges) > 0 {
+		return tileBoundary, touching
+	}
+
+	// No edge reaches the tile, so it is either entirely inside or entirely
+	// outside each polygon; its center decides.
+	c := vec{(minX + maxX) / 2, (minY + maxY) / 2}
+	for _, edges := range s.polygons {
+		if containsEvenOdd(edges, c) {
+			return tileInterior, touching
+		}
+	}
+	return tileDisjoint, touching
+}
+
+// segmentTouchesRect reports whether the segment intersects the closed
+// rectangle, using Liang-Barsky clipping.
+func segmentTouchesRect(e edge, minX, minY, maxX, maxY float64) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := e.b.x-e.a.x, e.b.y-e.a.y
+	for _, c := range [4][2]float64{
+		{-dx, e.a.x - minX},
+		{dx, maxX - e.a.x},
+		{-dy, e.a.y - minY},
+		{dy, maxY - e.a.y},
+	} {
+		p, q := c[0], c[1]
+		if p == 0 {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// containsEvenOdd reports whether p lies inside the rings formed by edges,
+// counting holes by the even-odd rule.
+func containsEvenOdd(edges []edge, p vec) bool {
+	inside := false
+	for _, e := range edges {
+		if (e.a.y <= p.y) != (e.b.y <= p.y) &&
+			p.x < e.a.x+(p.y-e.a.y)*(e.b.x-e.a.x)/(e.b.y-e.a.y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
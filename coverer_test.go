@@ -0,0 +1,196 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// assertCovers checks that every tile of want has exactly one ancestor (or
+// itself) in covering, and that the covering has no overlapping keys.
+func assertCovers(t *testing.T, name string, covering []QuadKey, want []QuadKey) {
+	t.Helper()
+	set := map[QuadKey]bool{}
+	for _, k := range covering {
+		set[k] = true
+	}
+	for _, k := range covering {
+		for z := 1; z < k.Z(); z++ {
+			if set[k[:z]] {
+				t.Fatalf("%s: %q overlaps its ancestor %q", name, k, k[:z])
+			}
+		}
+	}
+	for _, k := range want {
+		found := false
+		for z := 1; z <= k.Z(); z++ {
+			if set[k[:z]] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("%s: tile %q is not covered", name, k)
+		}
+	}
+}
+
+func TestRegionCovererPolygon(t *testing.T) {
+	poly := orb.Polygon{{
+		{139.0, 35.0}, {140.5, 35.2}, {140.0, 36.5}, {139.2, 36.0}, {139.0, 35.0},
+	}}
+	rc := RegionCoverer{MinZoom: 6, MaxZoom: 12}
+	got := rc.Covering(poly)
+	assertSorted(t, "covering", got)
+	assertCovers(t, "covering", got, Cover(poly, 12))
+
+	fine := Cover(poly, 12)
+	if !(len(got) < len(fine)/2) {
+		t.Fatalf("expected mixed zoom covering to be far smaller: %d vs %d", len(got), len(fine))
+	}
+
+	zooms := map[int]bool{}
+	for _, k := range got {
+		if k.Z() < 6 || k.Z() > 12 {
+			t.Fatalf("key %q outside the zoom range", k)
+		}
+		zooms[k.Z()] = true
+	}
+	if len(zooms) < 3 {
+		t.Fatalf("expected keys at several zooms, got %v", zooms)
+	}
+}
+
+func TestRegionCovererMaxCells(t *testing.T) {
+	poly := orb.Polygon{{
+		{139.0, 35.0}, {140.5, 35.2}, {140.0, 36.5}, {139.2, 36.0}, {139.0, 35.0},
+	}}
+	for _, maxCells := range []int{8, 20, 50} {
+		rc := RegionCoverer{MinZoom: 6, MaxZoom: 14, MaxCells: maxCells}
+		got := rc.Covering(poly)
+		if len(got) > maxCells {
+			t.Fatalf("max cells %d: got %d keys", maxCells, len(got))
+		}
+		assertCovers(t, "capped covering", got, Cover(poly, 14))
+	}
+
+	// The initial covering at MinZoom is kept even if it exceeds the cap.
+	rc := RegionCoverer{MinZoom: 10, MaxZoom: 12, MaxCells: 1}
+	got := rc.Covering(poly)
+	assertEqualInt(t, "uncapped initial covering", len(got), len(Cover(poly, 10)))
+}
+
+func TestRegionCovererLine(t *testing.T) {
+	ls := orb.LineString{{139.70, 35.60}, {139.80, 35.70}}
+	rc := RegionCoverer{MinZoom: 8, MaxZoom: 14}
+	got := rc.Covering(ls)
+
+	// Lines have no interior, so everything is refined to MaxZoom.
+	for _, k := range got {
+		if k.Z() != 14 {
+			t.Fatalf("line tile %q not at max zoom", k)
+		}
+	}
+	assertSameKeys(t, "line covering", got, Cover(ls, 14))
+}
+
+func TestRegionCovererDefaults(t *testing.T) {
+	p := orb.Point{10, 10}
+	got := RegionCoverer{}.Covering(p)
+	if len(got) != 1 || got[0] != FromPoint(p, 1) {
+		t.Fatalf("zero value coverer: got %v", got)
+	}
+	if got := (RegionCoverer{MinZoom: 3, MaxZoom: 5}).Covering(nil); len(got) != 0 {
+		t.Fatalf("expected no keys for nil geometry, got %v", got)
+	}
+}
+
+func TestCoverPyramid(t *testing.T) {
+	hole := orb.Ring{{139.4, 35.5}, {139.4, 35.8}, {139.7, 35.8}, {139.7, 35.5}, {139.4, 35.5}}
+	poly := orb.Polygon{{
+		{139.0, 35.0}, {140.5, 35.2}, {140.0, 36.5}, {139.2, 36.0}, {139.0, 35.0},
+	}, hole}
+	line := orb.LineString{{-20.3, -10.1}, {15.7, 30.2}}
+
+	for name, g := range map[string]orb.Geometry{"polygon": poly, "line": line} {
+		levels := CoverPyramid(g, 6, 12)
+		assertEqualInt(t, name+" levels", len(levels), 7)
+		for i, keys := range levels {
+			z := 6 + i
+			want := map[QuadKey]bool{}
+			for _, k := range Cover(g, z) {
+				want[k] = true
+			}
+			assertKeySet(t, name, keys, want)
+			assertSorted(t, name, keys)
+			if i == 0 {
+				continue
+			}
+			parents := map[QuadKey]bool{}
+			for _, k := range levels[i-1] {
+				parents[k] = true
+			}
+			for _, k := range keys {
+				if !parents[k[:len(k)-1]] {
+					t.Fatalf("%s: parent of %q missing from zoom %d", name, k, z-1)
+				}
+			}
+		}
+	}
+}
+
+func TestCoverPyramidMatchesWholeShape(t *testing.T) {
+	// A dense ring with a hole and a line through it: children are tested
+	// against their parent's edges only, which must not change any level.
+	ring, hole := orb.Ring{}, orb.Ring{}
+	for i := range 2000 {
+		a := 2 * math.Pi * float64(i) / 2000
+		r := 1 + 0.3*math.Sin(7*a)
+		ring = append(ring, orb.Point{139.5 + r*math.Cos(a), 35.5 + r*math.Sin(a)})
+		hole = append(hole, orb.Point{139.5 + 0.2*math.Cos(-a), 35.5 + 0.2*math.Sin(-a)})
+	}
+	ring, hole = append(ring, ring[0]), append(hole, hole[0])
+	g := orb.Collection{orb.Polygon{ring, hole}, orb.LineString{{138, 35.5}, {141, 35.6}}, orb.Point{139.5, 35.5}}
+
+	s := newShape(g)
+	levels := CoverPyramid(g, 6, 13)
+	for i := 1; i < len(levels); i++ {
+		want := []QuadKey{}
+		for _, key := range levels[i-1] {
+			x, y, z := key.XYZ()
+			parent := s.relate(x, y, z)
+			for _, child := range key.Children() {
+				x, y, z := child.XYZ()
+				if parent == tileInterior || s.relate(x, y, z) != tileDisjoint {
+					want = append(want, child)
+				}
+			}
+		}
+		if !slices.Equal(levels[i], want) {
+			t.Fatalf("zoom %d: got %d keys, want %d", 6+i, len(levels[i]), len(want))
+		}
+	}
+}
+
+func TestIterCoverPyramidStopsEarly(t *testing.T) {
+	poly := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	zooms := []int{}
+	for z := range IterCoverPyramid(poly, 0, 20) {
+		zooms = append(zooms, z)
+		if z == 3 {
+			break
+		}
+	}
+	if len(zooms) != 3 || zooms[0] != 1 || zooms[2] != 3 {
+		t.Fatalf("unexpected zooms %v", zooms)
+	}
+
+	if got := CoverPyramid(poly, 5, 4); len(got) != 0 {
+		t.Fatalf("expected no levels for an empty range, got %d", len(got))
+	}
+	if got := CoverPyramid(nil, 1, 4); len(got) != 0 {
+		t.Fatalf("expected no levels for nil geometry, got %d", len(got))
+	}
+}
@@ -0,0 +1,189 @@
+package quadkey
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"iter"
+	"math"
+	"strconv"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+	"github.com/paulmach/orb/encoding/wkb"
+	"github.com/paulmach/orb/encoding/wkt"
+)
+
+// wgs84SRID is the spatial reference ID of lon/lat coordinates on WGS 84.
+const wgs84SRID = 4326
+
+// --------------------------
+// geometry export
+// --------------------------
+
+// ToMultiPolygon returns the tiles of the keys as one MultiPolygon, one
+// polygon per key in order. Invalid keys are skipped.
+func ToMultiPolygon(keys ...QuadKey) orb.MultiPolygon {
+	mp := make(orb.MultiPolygon, 0, len(keys))
+	for _, key := range keys {
+		if key.Valid() == nil {
+			mp = append(mp, key.ToPolygon())
+		}
+	}
+	return mp
+}
+
+// GridLines returns the edges of the tiles KeysInBound returns for the bound
+// at zoom, each shared edge once: one line per grid column and row boundary,
+// spanning the whole range. Rendering a grid this way is far lighter than
+// drawing every tile polygon.
+func GridLines(bound orb.Bound, zoom int) orb.MultiLineString {
+	minX, minY, maxX, maxY := tileRange(bound, zoom)
+	if maxX < minX || maxY < minY {
+		return orb.MultiLineString{}
+	}
+
+	n := math.Exp2(float64(zoom))
+	west, east := tileLon(float64(minX), n), tileLon(float64(maxX+1), n)
+	north, south := tileLat(float64(minY), n), tileLat(float64(maxY+1), n)
+	lines := make(orb.MultiLineString, 0, maxX-minX+maxY-minY+4)
+	for x := minX; x <= maxX+1; x++ {
+		lon := tileLon(float64(x), n)
+		lines = append(lines, orb.LineString{{lon, south}, {lon, north}})
+	}
+	for y := minY; y <= maxY+1; y++ {
+		lat := tileLat(float64(y), n)
+		lines = append(lines, orb.LineString{{west, lat}, {east, lat}})
+	}
+	return lines
+}
+
+// WKT returns the tile polygon as well-known text, such as
+// "POLYGON((0 0,180 0,180 85.05112877980659,...))" for key "1", ready to
+// paste into a PostGIS query or QGIS. Invalid keys return "".
+func (key QuadKey) WKT() string {
+	if key.Valid() != nil {
+		return ""
+	}
+	return wkt.MarshalString(key.ToPolygon())
+}
+
+// MultiPolygonWKT returns the tiles of the keys as a MULTIPOLYGON in
+// well-known text, as ToMultiPolygon builds it. No valid keys give
+// "MULTIPOLYGON EMPTY".
+func MultiPolygonWKT(keys ...QuadKey) string {
+	return wkt.MarshalString(ToMultiPolygon(keys...))
+}
+
+// WKB returns the tile polygon as little-endian well-known binary, for
+// binding as a parameter of a PostGIS or SpatiaLite insert. An error is
+// returned if the key is invalid.
+func (key QuadKey) WKB() ([]byte, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	return wkb.Marshal(key.ToPolygon())
+}
+
+// EWKB is WKB in PostGIS's extended form, tagged with SRID 4326 so the
+// column's spatial reference check passes without ST_SetSRID.
+func (key QuadKey) EWKB() ([]byte, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	return ewkb.Marshal(key.ToPolygon(), wgs84SRID)
+}
+
+// ToKML returns a KML document with one Placemark per key, named after the
+// key and holding the tile polygon, for review in Google Earth. Invalid keys
+// are skipped.
+func ToKML(keys ...QuadKey) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2"><Document>` + "\n")
+	for _, key := range keys {
+		if key.Valid() != nil {
+			continue
+		}
+		// Keys are digits only, so the name needs no escaping.
+		b.WriteString("<Placemark><name>" + string(key) + "</name>")
+		b.WriteString("<Polygon><outerBoundaryIs><LinearRing><coordinates>")
+		for i, p := range key.ToPolygon()[0] {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.Write(strconv.AppendFloat(nil, p[0], 'f', -1, 64))
+			b.WriteByte(',')
+			b.Write(strconv.AppendFloat(nil, p[1], 'f', -1, 64))
+		}
+		b.WriteString("</coordinates></LinearRing></outerBoundaryIs></Polygon></Placemark>\n")
+	}
+	b.WriteString("</Document></kml>\n")
+	return b.Bytes()
+}
+
+// csvHeader is the first row written by WriteCSV.
+var csvHeader = []string{"key", "z", "x", "y", "west", "south", "east", "north"}
+
+// WriteCSV writes a header row and one key,z,x,y,west,south,east,north row
+// per key to w, for spreadsheet QA and CSV-loading engines. An error is
+// returned if any key is invalid, in which case the rows before it have
+// been written.
+func WriteCSV(w io.Writer, keys ...QuadKey) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			cw.Flush()
+			return err
+		}
+		if err := cw.Write(csvRecord(key)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteFeaturesND streams the keys to w as newline-delimited GeoJSON, one
+// feature per line as ToFeature builds it, without holding a collection in
+// memory, so it can write coverages of any size straight from an iterator
+// such as IterKeysInBound. An error is returned if any key is invalid, in
+// which case the features before it have been written.
+func WriteFeaturesND(w io.Writer, keys iter.Seq[QuadKey]) error {
+	bw := bufio.NewWriter(w)
+	for key := range keys {
+		if err := key.Valid(); err != nil {
+			bw.Flush()
+			return err
+		}
+		data, err := key.ToFeature().MarshalJSON()
+		if err != nil {
+			return err
+		}
+		bw.Write(data)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// csvRecord returns the csvHeader columns of a valid key.
+func csvRecord(key QuadKey) []string {
+	x, y, z := key.XYZ()
+	b := key.Bound()
+	return []string{
+		string(key),
+		strconv.Itoa(z),
+		strconv.Itoa(x),
+		strconv.Itoa(y),
+		strconv.FormatFloat(b.Min[0], 'f', -1, 64),
+		strconv.FormatFloat(b.Min[1], 'f', -1, 64),
+		strconv.FormatFloat(b.Max[0], 'f', -1, 64),
+		strconv.FormatFloat(b.Max[1], 'f', -1, 64),
+	}
+}
@@ -0,0 +1,190 @@
+package quadkey
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+	"github.com/paulmach/orb/encoding/wkb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestGridLines(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{-100, -30}, Max: orb.Point{-10, 30}}
+	lines := GridLines(bound, 3)
+	// Columns 1..3 and rows 3..4: 4 vertical and 3 horizontal lines.
+	if len(lines) != 7 {
+		t.Fatalf("got %d lines, want 7", len(lines))
+	}
+	span := FromXYZ(1, 3, 3).Bound().Union(FromXYZ(3, 4, 3).Bound())
+	assertNear(t, "west", span.Min[0], -135, 1e-12)
+	assertNear(t, "east", span.Max[0], 0, 1e-12)
+	for _, ls := range lines {
+		if len(ls) != 2 {
+			t.Fatalf("expected two-point lines, got %v", ls)
+		}
+		if !span.Contains(ls[0]) || !span.Contains(ls[1]) {
+			t.Fatalf("line %v is outside the grid %v", ls, span)
+		}
+	}
+	if lines[0][0] != span.Min || lines[3][1] != span.Max {
+		t.Fatalf("unexpected vertical lines %v", lines[:4])
+	}
+	assertNear(t, "equator", lines[5][0][1], 0, 1e-12)
+
+	// Every tile edge lies on a grid line.
+	for _, key := range KeysInBound(bound, 3) {
+		b := key.Bound()
+		if !slices.ContainsFunc(lines, func(ls orb.LineString) bool { return ls[0][0] == b.Min[0] }) ||
+			!slices.ContainsFunc(lines, func(ls orb.LineString) bool { return ls[0][1] == b.Max[1] }) {
+			t.Fatalf("tile %s edges are not on the grid", key)
+		}
+	}
+
+	// A single tile is outlined by two lines each way.
+	c := FromXYZ(5, 9, 5).Center()
+	if got := GridLines(orb.Bound{Min: c, Max: c}, 5); len(got) != 4 {
+		t.Fatalf("got %d lines for one tile, want 4", len(got))
+	}
+}
+
+func TestWKT(t *testing.T) {
+	if got, want := QuadKey("1").WKT(), "POLYGON((0 0,180 0,180 85.05112877980659,0 85.05112877980659,0 0))"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := QuadKey("01a").WKT(); got != "" {
+		t.Fatalf("expected empty WKT for an invalid key, got %q", got)
+	}
+}
+
+func TestMultiPolygonWKT(t *testing.T) {
+	got := MultiPolygonWKT("1", "01a", "3")
+	want := "MULTIPOLYGON(((0 0,180 0,180 85.05112877980659,0 85.05112877980659,0 0)),((0 -85.05112877980659,180 -85.05112877980659,180 0,0 0,0 -85.05112877980659)))"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := MultiPolygonWKT(); got != "MULTIPOLYGON EMPTY" {
+		t.Fatalf("got %q for no keys", got)
+	}
+	if mp := ToMultiPolygon("0", "01", "0x"); len(mp) != 2 || !strings.HasPrefix(MultiPolygonWKT("0"), "MULTIPOLYGON(((-180") {
+		t.Fatalf("unexpected multipolygon %v", mp)
+	}
+}
+
+func TestWKB(t *testing.T) {
+	key := QuadKey("13300221")
+	data, err := key.WKB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Little-endian polygon: byte order 1, type 3.
+	if data[0] != 1 || data[1] != 3 {
+		t.Fatalf("unexpected header % x", data[:5])
+	}
+	g, err := wkb.Unmarshal(data)
+	if err != nil || !orb.Equal(g, key.ToPolygon()) {
+		t.Fatalf("round trip: got %v, err %v", g, err)
+	}
+
+	data, err = key.EWKB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g, srid, err := ewkb.Unmarshal(data)
+	if err != nil || srid != 4326 || !orb.Equal(g, key.ToPolygon()) {
+		t.Fatalf("ewkb round trip: got %v, srid %d, err %v", g, srid, err)
+	}
+
+	if _, err := QuadKey("01a").WKB(); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := QuadKey("").EWKB(); err == nil {
+		t.Fatalf("expected error for an empty key")
+	}
+}
+
+func TestToKML(t *testing.T) {
+	data := ToKML("1", "01a", "3")
+	var doc struct {
+		Placemarks []struct {
+			Name        string `xml:"name"`
+			Coordinates string `xml:"Polygon>outerBoundaryIs>LinearRing>coordinates"`
+		} `xml:"Document>Placemark"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid KML: %v\n%s", err, data)
+	}
+	if len(doc.Placemarks) != 2 {
+		t.Fatalf("got %d placemarks, want 2", len(doc.Placemarks))
+	}
+	pm := doc.Placemarks[0]
+	if pm.Name != "1" {
+		t.Fatalf("got name %q, want %q", pm.Name, "1")
+	}
+	if want := "0,0 180,0 180,85.05112877980659 0,85.05112877980659 0,0"; pm.Coordinates != want {
+		t.Fatalf("got coordinates %q, want %q", pm.Coordinates, want)
+	}
+	if doc.Placemarks[1].Name != "3" {
+		t.Fatalf("got name %q, want %q", doc.Placemarks[1].Name, "3")
+	}
+
+	doc.Placemarks = nil
+	if err := xml.Unmarshal(ToKML(), &doc); err != nil || len(doc.Placemarks) != 0 {
+		t.Fatalf("empty document: %d placemarks, err %v", len(doc.Placemarks), err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSV(&b, "1", "21"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "key,z,x,y,west,south,east,north\n" +
+		"1,1,1,0,0,0,180,85.05112877980659\n" +
+		"21,2,1,2,-90,-66.51326044311185,0,0\n"
+	if b.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteCSV(&b, "0", "01a"); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if got := strings.Count(b.String(), "\n"); got != 2 {
+		t.Fatalf("got %d rows before the invalid key, want 2", got)
+	}
+}
+
+func TestWriteFeaturesND(t *testing.T) {
+	var b strings.Builder
+	bound := orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}}
+	if err := WriteFeaturesND(&b, IterKeysInBound(bound, 4)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	want := KeysInBound(bound, 4)
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(want))
+	}
+	for i, line := range lines {
+		f, err := geojson.UnmarshalFeature([]byte(line))
+		if err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if f.ID != string(want[i]) || !orb.Equal(f.Geometry, want[i].ToPolygon()) {
+			t.Fatalf("line %d: got feature %v %v, want %v", i, f.ID, f.Geometry, want[i])
+		}
+	}
+
+	b.Reset()
+	if err := WriteFeaturesND(&b, slices.Values([]QuadKey{"0", "01a"})); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if got := strings.Count(b.String(), "\n"); got != 1 {
+		t.Fatalf("got %d lines before the invalid key, want 1", got)
+	}
+}
@@ -0,0 +1,174 @@
+package quadkey
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"math"
+)
+
+// --------------------------
+// struct Filter
+// --------------------------
+
+// filterFormatVersion is the first byte of Filter.MarshalBinary output.
+const filterFormatVersion = 1
+
+// zeroFilterKeys and zeroFilterRate size a zero Filter on its first Add.
+const (
+	zeroFilterKeys = 1024
+	zeroFilterRate = 0.01
+)
+
+// Filter is a Bloom filter over keys: an approximate set that never misses a
+// key it holds, but may report a few keys it does not, at a false-positive
+// rate fixed when it is created. It takes about 1.2 bytes per key at a 1%
+// rate, whatever the keys' zoom, and can be shipped with MarshalBinary.
+//
+// The zero Filter holds no keys and is sized on its first Add as
+// NewFilter(zeroFilterKeys, zeroFilterRate) would size it.
+//
+// A Filter is not safe for concurrent use while keys are being added.
+type Filter struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	hashes int
+}
+
+// NewFilter returns an empty filter sized for n keys at the false-positive
+// rate fpRate, which is clamped to [1e-9, 0.5].
+func NewFilter(n int, fpRate float64) *Filter {
+	n = max(n, 1)
+	if !(fpRate > 1e-9) {
+		fpRate = 1e-9
+	}
+	fpRate = math.Min(fpRate, 0.5)
+
+	// Optimal sizing: m = -n ln p / ln²2 bits and k = m/n ln 2 hashes.
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	return &Filter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: max(k, 1),
+	}
+}
+
+// FilterFromKeys returns a filter holding keys at the false-positive rate
+// fpRate.
+func FilterFromKeys(keys []QuadKey, fpRate float64) *Filter {
+	f := NewFilter(len(keys), fpRate)
+	for _, key := range keys {
+		f.Add(key)
+	}
+	return f
+}
+
+// FilterFromSeq returns a filter holding the keys of seq, sized for n keys at
+// the false-positive rate fpRate. Keys are streamed, so coverages too large
+// to hold in memory can be filtered; n should be an estimate such as
+// CountKeysInBound, as more keys than n raise the false-positive rate.
+func FilterFromSeq(seq iter.Seq[QuadKey], n int, fpRate float64) *Filter {
+	f := NewFilter(n, fpRate)
+	for key := range seq {
+		f.Add(key)
+	}
+	return f
+}
+
+// Add inserts the key into the filter.
+func (f *Filter) Add(key QuadKey) {
+	if f.m == 0 {
+		*f = *NewFilter(zeroFilterKeys, zeroFilterRate)
+	}
+	h1, h2 := filterHashes(key)
+	for i := range f.hashes {
+		bit := (h1 + uint64(i)*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains reports whether the key may be in the filter. False means the key
+// was never added; true is wrong at about the filter's false-positive rate.
+func (f *Filter) Contains(key QuadKey) bool {
+	if f.m == 0 {
+		return false
+	}
+	h1, h2 := filterHashes(key)
+	for i := range f.hashes {
+		bit := (h1 + uint64(i)*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the filter as a version byte, the hash count and
+// bit count as uvarints, and the bits as little-endian 64-bit words.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 1+2*binary.MaxVarintLen64+8*len(f.bits))
+	data = append(data, filterFormatVersion)
+	data = binary.AppendUvarint(data, uint64(f.hashes))
+	data = binary.AppendUvarint(data, f.m)
+	for _, w := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary, replacing the
+// receiver's contents.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("filter data is empty")
+	}
+	if data[0] != filterFormatVersion {
+		return fmt.Errorf("unknown filter data version %d", data[0])
+	}
+	data = data[1:]
+
+	k, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("filter data is truncated")
+	}
+	data = data[n:]
+	m, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("filter data is truncated")
+	}
+	data = data[n:]
+
+	if k == 0 || k > 64 || m == 0 {
+		return fmt.Errorf("invalid filter parameters: %d hashes over %d bits", k, m)
+	}
+	// Compare word counts, which cannot overflow for any m, before sizing.
+	if words := (m-1)/64 + 1; len(data)%8 != 0 || uint64(len(data)/8) != words {
+		return fmt.Errorf("filter data holds %d bytes for %d bits", len(data), m)
+	}
+
+	bits := make([]uint64, len(data)/8)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	*f = Filter{bits: bits, m: m, hashes: int(k)}
+	return nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// filterHashes derives the two hashes combined into each of a filter's bit
+// positions (Kirsch-Mitzenmacher double hashing) from one FNV-1a hash.
+func filterHashes(key QuadKey) (h1, h2 uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+	// The rotated hash is kept nonzero so the positions never collapse into
+	// one.
+	return sum, (sum>>32 | sum<<32) | 1
+}
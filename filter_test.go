@@ -0,0 +1,89 @@
+package quadkey
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestFilterNoFalseNegatives(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{140.0, 36.0}}
+	keys := KeysInBound(bound, 14)
+	f := FilterFromKeys(keys, 0.01)
+	for _, k := range keys {
+		if !f.Contains(k) {
+			t.Fatalf("filter misses %q", k)
+		}
+	}
+}
+
+func TestFilterFalsePositiveRate(t *testing.T) {
+	inside := orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{140.0, 36.0}}
+	f := FilterFromSeq(IterKeysInBound(inside, 14), int(CountKeysInBound(inside, 14)), 0.01)
+
+	outside := orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{-8, -8}}
+	probes, hits := 0, 0
+	for k := range IterKeysInBound(outside, 14) {
+		probes++
+		if f.Contains(k) {
+			hits++
+		}
+	}
+	if rate := float64(hits) / float64(probes); rate > 0.02 {
+		t.Fatalf("false-positive rate %.4f over %d probes, want about 0.01", rate, probes)
+	}
+}
+
+func TestFilterMarshalBinary(t *testing.T) {
+	keys := []QuadKey{"0123", "3", "2222", "130"}
+	f := FilterFromKeys(keys, 0.001)
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded Filter
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range keys {
+		if !decoded.Contains(k) {
+			t.Fatalf("decoded filter misses %q", k)
+		}
+	}
+	for _, k := range []QuadKey{"0", "1", "0122", "33"} {
+		if decoded.Contains(k) != f.Contains(k) {
+			t.Fatalf("decoded filter differs on %q", k)
+		}
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"version":   {9, 1, 64},
+		"truncated": data[:len(data)-1],
+		"hashes":    {filterFormatVersion, 0, 64, 0, 0, 0, 0, 0, 0, 0, 0},
+		// 2^64-1 bits, whose byte count wraps to 0 if computed naively.
+		"huge bits": binary.AppendUvarint([]byte{filterFormatVersion, 3}, math.MaxUint64),
+		"odd bytes": {filterFormatVersion, 3, 64, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	} {
+		if err := new(Filter).UnmarshalBinary(bad); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestFilterZeroValue(t *testing.T) {
+	var f Filter
+	if f.Contains("0123") {
+		t.Fatalf("the zero filter holds no keys")
+	}
+	f.Add("0123")
+	if !f.Contains("0123") {
+		t.Fatalf("the zero filter misses an added key")
+	}
+	if f.Contains("3") && f.Contains("2") && f.Contains("10") {
+		t.Fatalf("the zero filter holds keys never added")
+	}
+}
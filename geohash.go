@@ -0,0 +1,126 @@
+package quadkey
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// geohash interop
+// --------------------------
+
+// geohashAlphabet is the geohash base32 alphabet.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// maxGeohashPrecision is the longest geohash the package produces; its cells
+// are a few centimeters across.
+const maxGeohashPrecision = 12
+
+// Geohash returns the geohash, of precision characters, of the cell holding
+// the key's center. Keys and geohash cells never line up exactly, so this is
+// a center-point mapping: the cell contains the center, not the whole tile.
+// Invalid keys and precisions outside [1, 12] return "".
+func (key QuadKey) Geohash(precision int) string {
+	if key.Valid() != nil || precision < 1 || precision > maxGeohashPrecision {
+		return ""
+	}
+	c := key.Center()
+	lon := [2]float64{-180, 180}
+	lat := [2]float64{-90, 90}
+
+	var b strings.Builder
+	b.Grow(precision)
+	even := true
+	ch, bit := 0, 0
+	for b.Len() < precision {
+		// Bits alternate between longitude and latitude, longitude first.
+		r, v := &lat, c.Lat()
+		if even {
+			r, v = &lon, c.Lon()
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			b.WriteByte(geohashAlphabet[ch])
+			ch, bit = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// FromGeohash returns the key at zoom holding the center of the geohash
+// cell, the inverse of Geohash's center-point mapping. Geohashes are case
+// insensitive. An error is returned for an invalid geohash or zoom.
+func FromGeohash(hash string, zoom int) (QuadKey, error) {
+	if zoom < 1 || zoom > MaxZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", zoom, MaxZoom)
+	}
+	b, err := geohashBound(hash)
+	if err != nil {
+		return "", err
+	}
+	return FromPoint(b.Center(), zoom), nil
+}
+
+// GeohashCover returns the keys at zoom covering the geohash cell, as
+// KeysInBound does for its bound, for joining geohash-partitioned data with
+// quadkey-partitioned data. An error is returned for an invalid geohash or
+// zoom.
+func GeohashCover(hash string, zoom int) ([]QuadKey, error) {
+	if zoom < 1 || zoom > MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, MaxZoom)
+	}
+	b, err := geohashBound(hash)
+	if err != nil {
+		return nil, err
+	}
+	return KeysInBound(b, zoom), nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// geohashBound decodes a geohash to the lon/lat bound of its cell.
+func geohashBound(hash string) (orb.Bound, error) {
+	if hash == "" {
+		return orb.Bound{}, errors.New("geohash is empty")
+	}
+	if len(hash) > maxGeohashPrecision {
+		return orb.Bound{}, fmt.Errorf("geohash %q is longer than %d characters", hash, maxGeohashPrecision)
+	}
+	lower := strings.ToLower(hash)
+	lon := [2]float64{-180, 180}
+	lat := [2]float64{-90, 90}
+	even := true
+	for i := 0; i < len(lower); i++ {
+		v := strings.IndexByte(geohashAlphabet, lower[i])
+		if v < 0 {
+			return orb.Bound{}, fmt.Errorf("geohash %q contains invalid character %q", hash, hash[i])
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &lat
+			if even {
+				r = &lon
+			}
+			mid := (r[0] + r[1]) / 2
+			if v>>bit&1 == 1 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return orb.Bound{Min: orb.Point{lon[0], lat[0]}, Max: orb.Point{lon[1], lat[1]}}, nil
+}
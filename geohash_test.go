@@ -0,0 +1,81 @@
+package quadkey
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestGeohash(t *testing.T) {
+	// The reference point of the geohash article: u4pruydqqvj.
+	key := FromLonLat(10.40744, 57.64911, 30)
+	if got := key.Geohash(9); got != "u4pruydqq" {
+		t.Fatalf("got %q, want %q", got, "u4pruydqq")
+	}
+	if got := key.Geohash(1); got != "u" {
+		t.Fatalf("got %q, want %q", got, "u")
+	}
+	for _, p := range []int{0, 13} {
+		if got := key.Geohash(p); got != "" {
+			t.Fatalf("precision %d: expected empty geohash, got %q", p, got)
+		}
+	}
+	if got := QuadKey("01a").Geohash(5); got != "" {
+		t.Fatalf("expected empty geohash for an invalid key, got %q", got)
+	}
+}
+
+func TestFromGeohash(t *testing.T) {
+	key, err := FromGeohash("u4pruydqqvj", 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := FromLonLat(10.40744, 57.64911, 16); key != want {
+		t.Fatalf("got %q, want %q", key, want)
+	}
+	if upper, _ := FromGeohash("U4PRUYDQQVJ", 16); upper != key {
+		t.Fatalf("upper case: got %q, want %q", upper, key)
+	}
+
+	// A tile's geohash maps back to the tile when the cell is smaller.
+	tile := FromLonLat(139.767125, 35.681236, 12)
+	if got, _ := FromGeohash(tile.Geohash(8), 12); got != tile {
+		t.Fatalf("round trip: got %q, want %q", got, tile)
+	}
+
+	for _, tt := range []struct {
+		hash string
+		zoom int
+	}{{"", 5}, {"u4a", 5}, {"u4p", 0}, {"u4pruydqqvjuu", 5}} {
+		if _, err := FromGeohash(tt.hash, tt.zoom); err == nil {
+			t.Fatalf("%q at zoom %d: expected error", tt.hash, tt.zoom)
+		}
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	keys, err := GeohashCover("u4pr", 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) == 0 {
+		t.Fatalf("expected keys")
+	}
+	cell, _ := geohashBound("u4pr")
+	for _, k := range keys {
+		if !k.IntersectsBound(cell) {
+			t.Fatalf("%q does not intersect the cell", k)
+		}
+	}
+	// The cell's corners are covered.
+	for _, p := range []orb.Point{{cell.Min[0] + 1e-9, cell.Max[1] - 1e-9}, {cell.Max[0] - 1e-9, cell.Min[1] + 1e-9}} {
+		if k := FromPoint(p, 12); !slices.Contains(keys, k) {
+			t.Fatalf("corner %v (%q) is not covered", p, k)
+		}
+	}
+
+	if _, err := GeohashCover("u4pr", 33); err == nil {
+		t.Fatalf("expected error for zoom 33")
+	}
+}
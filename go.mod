@@ -2,7 +2,10 @@ module github.com/nideojp/go-quadkey
 
 go 1.25.5
 
+require github.com/paulmach/orb v0.12.0
+
 require (
-	github.com/paulmach/orb v0.12.0 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/paulmach/protoscan v0.2.1 // indirect
 	go.mongodb.org/mongo-driver v1.11.4 // indirect
 )
@@ -0,0 +1,133 @@
+package quadkey
+
+import (
+	"cmp"
+	"slices"
+)
+
+// --------------------------
+// Hilbert curve
+// --------------------------
+
+// HilbertIndex returns the key's position along the Hilbert curve through
+// all tiles of its zoom, in [0, 4^z). Unlike Morton codes, consecutive
+// indexes are always adjacent tiles, which keeps tiles written in index
+// order close together in archives and object stores. The curve is the one
+// PMTiles uses; PMTilesID adds the per-zoom offset PMTiles tile IDs carry.
+// Invalid keys return 0.
+func (key QuadKey) HilbertIndex() uint64 {
+	ux, uy, z := key.tileXY()
+	if z < 1 {
+		return 0
+	}
+
+	var d uint64
+	for s := uint64(1) << (z - 1); s > 0; s >>= 1 {
+		rx, ry := ux&s != 0, uy&s != 0
+		quadrant := uint64(0)
+		switch {
+		case !rx && ry:
+			quadrant = 1
+		case rx && ry:
+			quadrant = 2
+		case rx && !ry:
+			quadrant = 3
+		}
+		d += s * s * quadrant
+		ux, uy = hilbertRotate(s, ux, uy, rx, ry)
+	}
+	return d
+}
+
+// FromHilbert returns the key at zoom z with the given Hilbert index, the
+// inverse of HilbertIndex. It returns "" if z is not between 1 and MaxZoom
+// or index is not below 4^z.
+func FromHilbert(index uint64, z int) QuadKey {
+	if z < 1 || z > MaxZoom || (z < MaxZoom && index>>(2*z) != 0) {
+		return ""
+	}
+
+	var x, y uint64
+	t := index
+	for s := uint64(1); s < 1<<z; s <<= 1 {
+		rx := t >> 1 & 1
+		ry := (t ^ rx) & 1
+		x, y = hilbertRotate(s, x, y, rx == 1, ry == 1)
+		x += s * rx
+		y += s * ry
+		t >>= 2
+	}
+	return tileKey(x, y, z)
+}
+
+// PMTilesID returns the key's tile ID in a PMTiles archive: the number of
+// tiles at all shallower zooms, (4^z - 1) / 3, plus its HilbertIndex. Invalid
+// keys and keys deeper than zoom 31 return 0.
+func (key QuadKey) PMTilesID() uint64 {
+	z := key.Z()
+	if key.Valid() != nil || z > 31 {
+		return 0
+	}
+	return hilbertOffset(z) + key.HilbertIndex()
+}
+
+// FromPMTilesID returns the key of a PMTiles tile ID, the inverse of
+// PMTilesID. ID 0, the zoom 0 world tile, has no key and returns "", as do
+// IDs beyond zoom 31.
+func FromPMTilesID(id uint64) QuadKey {
+	if id == 0 {
+		return ""
+	}
+	for z := 1; z <= 31; z++ {
+		if id < hilbertOffset(z+1) {
+			return FromHilbert(id-hilbertOffset(z), z)
+		}
+	}
+	return ""
+}
+
+// SortPMTiles sorts keys in place by PMTilesID, the order the PMTiles spec
+// recommends for writing tile data: zoom by zoom, along the Hilbert curve
+// within each zoom. Archives written in this order are clustered, which
+// lets readers fetch runs of neighboring tiles in one request. Invalid keys
+// sort first.
+func SortPMTiles(keys []QuadKey) {
+	type keyID struct {
+		key QuadKey
+		id  uint64
+	}
+	ids := make([]keyID, len(keys))
+	for i, key := range keys {
+		ids[i] = keyID{key, key.PMTilesID()}
+	}
+	slices.SortStableFunc(ids, func(a, b keyID) int { return cmp.Compare(a.id, b.id) })
+	for i := range ids {
+		keys[i] = ids[i].key
+	}
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// hilbertOffset returns the number of tiles at zooms below z, (4^z - 1) / 3.
+func hilbertOffset(z int) uint64 {
+	if z >= 32 {
+		// (4^32 - 1) / 3 without overflowing 4^32.
+		return (1<<64 - 1) / 3
+	}
+	return (1<<(2*z) - 1) / 3
+}
+
+// hilbertRotate flips and transposes a quadrant of side s so the curve's
+// sub-paths connect.
+func hilbertRotate(s, x, y uint64, rx, ry bool) (uint64, uint64) {
+	if !ry {
+		if rx {
+			x = s - 1 - x
+			y = s - 1 - y
+		}
+		x, y = y, x
+	}
+	return x, y
+}
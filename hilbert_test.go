@@ -0,0 +1,111 @@
+package quadkey
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHilbertIndex(t *testing.T) {
+	// Zoom 1 visits the quadrants NW, SW, SE, NE.
+	for i, key := range []QuadKey{"0", "2", "3", "1"} {
+		if got := key.HilbertIndex(); got != uint64(i) {
+			t.Fatalf("%q: got %d, want %d", key, got, i)
+		}
+	}
+
+	for z := 1; z <= 6; z++ {
+		n := 1 << z
+		seen := make([]bool, n*n)
+		var prev QuadKey
+		for d := uint64(0); d < uint64(n*n); d++ {
+			key := FromHilbert(d, z)
+			if got := key.HilbertIndex(); got != d {
+				t.Fatalf("zoom %d: index %d round-trips to %d via %q", z, d, got, key)
+			}
+			x, y, _ := key.XYZ()
+			if seen[y*n+x] {
+				t.Fatalf("zoom %d: tile %q visited twice", z, key)
+			}
+			seen[y*n+x] = true
+
+			// Consecutive indexes are edge-adjacent tiles.
+			if d > 0 {
+				px, py, _ := prev.XYZ()
+				if abs(px-x)+abs(py-y) != 1 {
+					t.Fatalf("zoom %d: %q and %q are not adjacent", z, prev, key)
+				}
+			}
+			prev = key
+		}
+	}
+
+	deep := QuadKey("31313131313131313131313131313131")
+	if got := FromHilbert(deep.HilbertIndex(), 32); got != deep {
+		t.Fatalf("zoom 32: got %q", got)
+	}
+}
+
+func TestPMTilesID(t *testing.T) {
+	// IDs from the PMTiles reference implementation's tests; the curve ends
+	// at the north-east tile.
+	tests := []struct {
+		x, y, z int
+		id      uint64
+	}{
+		{0, 0, 1, 1},
+		{0, 1, 1, 2},
+		{1, 1, 1, 3},
+		{1, 0, 1, 4},
+		{0, 0, 2, 5},
+		{0, 0, 3, 21},
+		{7, 0, 3, 84},
+	}
+	for _, tt := range tests {
+		key := FromXYZ(tt.x, tt.y, tt.z)
+		if got := key.PMTilesID(); got != tt.id {
+			t.Fatalf("(%d,%d,%d): got %d, want %d", tt.x, tt.y, tt.z, got, tt.id)
+		}
+		if got := FromPMTilesID(tt.id); got != key {
+			t.Fatalf("FromPMTilesID(%d): got %q, want %q", tt.id, got, key)
+		}
+	}
+
+	deep := FromXYZ(1<<31-1, 12345, 31)
+	if got := FromPMTilesID(deep.PMTilesID()); got != deep {
+		t.Fatalf("zoom 31: got %q", got)
+	}
+	if FromPMTilesID(0) != "" {
+		t.Fatalf("expected no key for the zoom 0 tile")
+	}
+}
+
+func TestHilbertInvalid(t *testing.T) {
+	if got := QuadKey("01a").HilbertIndex(); got != 0 {
+		t.Fatalf("invalid key: got %d", got)
+	}
+	if got := FromHilbert(16, 2); got != "" {
+		t.Fatalf("index beyond the zoom: got %q", got)
+	}
+	if got := FromHilbert(0, 0); got != "" {
+		t.Fatalf("zoom 0: got %q", got)
+	}
+	if got := QuadKey("01a").PMTilesID(); got != 0 {
+		t.Fatalf("invalid key: got %d", got)
+	}
+}
+
+func TestSortPMTiles(t *testing.T) {
+	keys := []QuadKey{"13", "1", "00", "3", "x", "0", "2"}
+	SortPMTiles(keys)
+	if want := []QuadKey{"x", "0", "2", "3", "1", "00"}; !slices.Equal(keys[:6], want) {
+		t.Fatalf("got %v, want %v first", keys, want)
+	}
+	if keys[6] != "13" {
+		t.Fatalf("got %q last, want %q", keys[6], "13")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1].PMTilesID() > keys[i].PMTilesID() {
+			t.Fatalf("keys %q and %q are out of order", keys[i-1], keys[i])
+		}
+	}
+}
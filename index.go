@@ -0,0 +1,312 @@
+package quadkey
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// An index file holds sorted keys in blocks of indexBlockSize keys. Each
+// block is front-coded (every key stores the length of the prefix it shares
+// with the previous key, then the rest of its digits) and deflated. After
+// the blocks comes the block index: the block count, then the offset, length
+// and first key of every block, as uvarints and raw digits. The file ends
+// with the index's offset as a little-endian uint64 and indexMagic.
+const (
+	indexBlockSize = 4096
+	indexMagic     = "QKI1"
+)
+
+// indexBlockMaxRaw bounds a decoded block: indexBlockSize keys of at most
+// MaxZoom digits, each after a one-byte shared length and suffix length.
+const indexBlockMaxRaw = indexBlockSize * (MaxZoom + 2)
+
+// indexBlock locates one block of an index file.
+type indexBlock struct {
+	first          QuadKey
+	offset, length uint64
+}
+
+// --------------------------
+// struct IndexWriter
+// --------------------------
+
+// IndexWriter writes a sorted key index file, to be read with OpenIndex.
+// Keys are added in ascending quadkey order; memory use is bounded by one
+// block, so indexes larger than memory can be written.
+type IndexWriter struct {
+	w      io.Writer
+	offset uint64
+	last   QuadKey
+	block  []QuadKey
+	blocks []indexBlock
+	err    error
+}
+
+// NewIndexWriter returns a writer of an index file to w.
+func NewIndexWriter(w io.Writer) *IndexWriter {
+	return &IndexWriter{w: w}
+}
+
+// Add appends a key to the index. Keys must be valid and strictly ascending
+// in quadkey order. Once writing fails, every call returns the error.
+func (iw *IndexWriter) Add(key QuadKey) error {
+	if iw.err != nil {
+		return iw.err
+	}
+	if err := key.Valid(); err != nil {
+		return err
+	}
+	if iw.last != "" && key <= iw.last {
+		return fmt.Errorf("key %q is not after %q", key, iw.last)
+	}
+
+	iw.last = key
+	iw.block = append(iw.block, key)
+	if len(iw.block) == indexBlockSize {
+		iw.err = iw.flush()
+	}
+	return iw.err
+}
+
+// Close writes the last block and the block index. It does not close the
+// underlying writer.
+func (iw *IndexWriter) Close() error {
+	if iw.err != nil {
+		return iw.err
+	}
+	if iw.err = iw.flush(); iw.err != nil {
+		return iw.err
+	}
+
+	index := binary.AppendUvarint(nil, uint64(len(iw.blocks)))
+	for _, b := range iw.blocks {
+		index = binary.AppendUvarint(index, b.offset)
+		index = binary.AppendUvarint(index, b.length)
+		index = binary.AppendUvarint(index, uint64(len(b.first)))
+		index = append(index, b.first...)
+	}
+	index = binary.LittleEndian.AppendUint64(index, iw.offset)
+	index = append(index, indexMagic...)
+	if _, err := iw.w.Write(index); err != nil {
+		iw.err = err
+		return err
+	}
+	iw.err = errors.New("index writer is closed")
+	return nil
+}
+
+// flush writes the pending keys as one block.
+func (iw *IndexWriter) flush() error {
+	if len(iw.block) == 0 {
+		return nil
+	}
+
+	raw := []byte{}
+	prev := QuadKey("")
+	for _, key := range iw.block {
+		shared := commonPrefixLen(prev, key)
+		raw = binary.AppendUvarint(raw, uint64(shared))
+		raw = binary.AppendUvarint(raw, uint64(len(key)-shared))
+		raw = append(raw, key[shared:]...)
+		prev = key
+	}
+
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	fw.Write(raw)
+	if err := fw.Close(); err != nil {
+		return err
+	}
+	if _, err := iw.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	iw.blocks = append(iw.blocks, indexBlock{first: iw.block[0], offset: iw.offset, length: uint64(buf.Len())})
+	iw.offset += uint64(buf.Len())
+	iw.block = iw.block[:0]
+	return nil
+}
+
+// --------------------------
+// struct Index
+// --------------------------
+
+// Index reads a key index file written by IndexWriter. Only the block index,
+// one key per block, is held in memory; lookups binary-search it and read
+// and decode a single block. An Index is safe for concurrent use if its
+// io.ReaderAt is.
+type Index struct {
+	r      io.ReaderAt
+	blocks []indexBlock
+}
+
+// OpenIndex reads the block index of the index file of the given size.
+func OpenIndex(r io.ReaderAt, size int64) (*Index, error) {
+	trailer := int64(8 + len(indexMagic))
+	if size < trailer {
+		return nil, errors.New("index file is too short")
+	}
+	buf := make([]byte, trailer)
+	if _, err := r.ReadAt(buf, size-trailer); err != nil {
+		return nil, err
+	}
+	if string(buf[8:]) != indexMagic {
+		return nil, errors.New("not an index file")
+	}
+	offset := binary.LittleEndian.Uint64(buf)
+	if offset > uint64(size-trailer) {
+		return nil, errors.New("index file is corrupt")
+	}
+
+	data := make([]byte, uint64(size-trailer)-offset)
+	if _, err := r.ReadAt(data, int64(offset)); err != nil {
+		return nil, err
+	}
+	d := decoder{data: data}
+	count := d.uvarint()
+	if d.err == nil && count > uint64(len(data)) {
+		return nil, errors.New("index file is corrupt")
+	}
+	blocks := make([]indexBlock, 0, count)
+	for range count {
+		b := indexBlock{offset: d.uvarint(), length: d.uvarint()}
+		b.first = QuadKey(d.bytes(d.uvarint()))
+		if d.err != nil {
+			return nil, d.err
+		}
+		if b.length > offset || b.offset > offset-b.length {
+			return nil, errors.New("index file is corrupt")
+		}
+		blocks = append(blocks, b)
+	}
+	return &Index{r: r, blocks: blocks}, nil
+}
+
+// Contains reports whether the key is in the index.
+func (ix *Index) Contains(key QuadKey) (bool, error) {
+	i := ix.blockFor(key)
+	if i < 0 {
+		return false, nil
+	}
+	keys, err := ix.readBlock(i)
+	if err != nil {
+		return false, err
+	}
+	j := sort.Search(len(keys), func(j int) bool { return keys[j] >= key })
+	return j < len(keys) && keys[j] == key, nil
+}
+
+// ScanPrefix yields, in order, every key in the index that starts with
+// prefix: the prefix key itself if present and all of its descendants. An
+// empty prefix yields every key. Reading stops at the first error, which is
+// yielded with an empty key.
+func (ix *Index) ScanPrefix(prefix QuadKey) iter.Seq2[QuadKey, error] {
+	return func(yield func(QuadKey, error) bool) {
+		for i := max(ix.blockFor(prefix), 0); i < len(ix.blocks); i++ {
+			keys, err := ix.readBlock(i)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, key := range keys {
+				if strings.HasPrefix(string(key), string(prefix)) {
+					if !yield(key, nil) {
+						return
+					}
+				} else if key > prefix {
+					return
+				}
+			}
+		}
+	}
+}
+
+// blockFor returns the last block whose first key is at most key, or -1.
+func (ix *Index) blockFor(key QuadKey) int {
+	return sort.Search(len(ix.blocks), func(i int) bool { return ix.blocks[i].first > key }) - 1
+}
+
+// readBlock reads and decodes block i.
+func (ix *Index) readBlock(i int) ([]QuadKey, error) {
+	b := ix.blocks[i]
+	compressed := make([]byte, b.length)
+	if _, err := ix.r.ReadAt(compressed, int64(b.offset)); err != nil {
+		return nil, err
+	}
+	fr := flate.NewReader(bytes.NewReader(compressed))
+	defer fr.Close()
+	raw, err := io.ReadAll(io.LimitReader(fr, indexBlockMaxRaw+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > indexBlockMaxRaw {
+		return nil, errors.New("index block is corrupt")
+	}
+
+	keys := []QuadKey{}
+	d := decoder{data: raw}
+	prev := QuadKey("")
+	for len(d.data) > 0 && d.err == nil {
+		shared := d.uvarint()
+		suffix := d.bytes(d.uvarint())
+		if shared > uint64(len(prev)) {
+			return nil, errors.New("index block is corrupt")
+		}
+		prev = prev[:shared] + QuadKey(suffix)
+		keys = append(keys, prev)
+	}
+	return keys, d.err
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// decoder reads uvarints and byte strings, remembering the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errors.New("index data is truncated")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.err = errors.New("index data is truncated")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b.
+func commonPrefixLen(a, b QuadKey) int {
+	n := min(len(a), len(b))
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,161 @@
+package quadkey
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// writeIndex writes keys to an in-memory index file and opens it.
+func writeIndex(t *testing.T, keys []QuadKey) (*Index, int) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewIndexWriter(&buf)
+	for _, k := range keys {
+		if err := w.Add(k); err != nil {
+			t.Fatalf("add %q: %v", k, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	ix, err := OpenIndex(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return ix, buf.Len()
+}
+
+func TestIndexContains(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{140.0, 36.0}}
+	keys := KeysInBound(bound, 16)
+	slices.Sort(keys)
+	ix, size := writeIndex(t, keys)
+
+	if len(ix.blocks) < 2 {
+		t.Fatalf("expected several blocks, got %d", len(ix.blocks))
+	}
+	// Front coding and deflate beat 2 bits per digit.
+	if size > 4*len(keys) {
+		t.Fatalf("index of %d keys takes %d bytes", len(keys), size)
+	}
+
+	for i, k := range keys {
+		if i%97 != 0 && i != len(keys)-1 {
+			continue
+		}
+		if ok, err := ix.Contains(k); err != nil || !ok {
+			t.Fatalf("contains %q: got %v, err %v", k, ok, err)
+		}
+	}
+	for _, k := range []QuadKey{"0", "3", keys[0][:15], keys[0] + "0", "0000000000000000"} {
+		if ok, err := ix.Contains(k); err != nil || ok {
+			t.Fatalf("contains %q: got %v, err %v", k, ok, err)
+		}
+	}
+}
+
+func TestIndexScanPrefix(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{140.0, 36.0}}
+	keys := append(KeysInBound(bound, 14), KeysInBound(bound, 9)...)
+	slices.Sort(keys)
+	ix, _ := writeIndex(t, keys)
+
+	for _, prefix := range []QuadKey{"", "1", keys[0][:9], keys[len(keys)/2][:11], keys[len(keys)-1], "0"} {
+		want := []QuadKey{}
+		for _, k := range keys {
+			if strings.HasPrefix(string(k), string(prefix)) {
+				want = append(want, k)
+			}
+		}
+		got := []QuadKey{}
+		for k, err := range ix.ScanPrefix(prefix) {
+			if err != nil {
+				t.Fatalf("scan %q: %v", prefix, err)
+			}
+			got = append(got, k)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("scan %q: got %d keys, want %d", prefix, len(got), len(want))
+		}
+	}
+}
+
+func TestIndexWriterErrors(t *testing.T) {
+	w := NewIndexWriter(&bytes.Buffer{})
+	if err := w.Add("0123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range []QuadKey{"0123", "012", "01a"} {
+		if err := w.Add(k); err == nil {
+			t.Fatalf("add %q: expected error", k)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := w.Add("3"); err == nil {
+		t.Fatalf("expected error after close")
+	}
+}
+
+func TestOpenIndexInvalid(t *testing.T) {
+	ix, _ := writeIndex(t, nil)
+	if ok, err := ix.Contains("0"); err != nil || ok {
+		t.Fatalf("empty index: got %v, err %v", ok, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"short":  []byte("QKI1"),
+		"magic":  []byte("\x00\x00\x00\x00\x00\x00\x00\x00QKI0"),
+		"offset": []byte("\xff\x00\x00\x00\x00\x00\x00\x00QKI1"),
+	} {
+		if _, err := OpenIndex(bytes.NewReader(data), int64(len(data))); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	// A block whose offset plus length wraps around must not open.
+	data := []byte{0}
+	data = binary.AppendUvarint(data, 1)
+	data = binary.AppendUvarint(data, 1)
+	data = binary.AppendUvarint(data, math.MaxUint64)
+	data = binary.AppendUvarint(data, 1)
+	data = append(data, '0')
+	data = binary.LittleEndian.AppendUint64(data, 1)
+	data = append(data, indexMagic...)
+	if _, err := OpenIndex(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Fatalf("wrapping block length: expected error")
+	}
+}
+
+func TestIndexBlockTooLarge(t *testing.T) {
+	// A tiny block that inflates past any real block must be rejected.
+	var block bytes.Buffer
+	fw, _ := flate.NewWriter(&block, flate.BestCompression)
+	fw.Write(make([]byte, indexBlockMaxRaw+1))
+	fw.Close()
+
+	data := slices.Clone(block.Bytes())
+	data = binary.AppendUvarint(data, 1)
+	data = binary.AppendUvarint(data, 0)
+	data = binary.AppendUvarint(data, uint64(block.Len()))
+	data = binary.AppendUvarint(data, 1)
+	data = append(data, '0')
+	data = binary.LittleEndian.AppendUint64(data, uint64(block.Len()))
+	data = append(data, indexMagic...)
+
+	ix, err := OpenIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := ix.Contains("0"); err == nil {
+		t.Fatalf("expected an error for an oversized block")
+	}
+}
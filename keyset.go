@@ -0,0 +1,486 @@
+package quadkey
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/paulmach/orb/geojson"
+)
+
+// --------------------------
+// struct KeySet
+// --------------------------
+
+// arrayMax is the most values an array container holds before it becomes a
+// bitmap container: at 4096 values both take 8 KiB.
+const arrayMax = 4096
+
+// KeySet is an exact set of keys stored as compressed bitmaps, one per zoom,
+// over the keys' Morton codes in the style of roaring bitmaps: the codes are
+// split into chunks of 2^16 by their high bits, and each chunk is a sorted
+// array of 16-bit values while sparse and a 65536-bit bitmap once dense.
+// Dense coverages cost about one bit per tile and sparse ones two bytes,
+// against tens of bytes in a map[QuadKey]bool, and Union and Intersect work
+// a chunk at a time.
+//
+// A KeySet works with the rest of the key set API: Covers and
+// CoversDescendant answer hierarchical membership, Compact and
+// ToFeatureCollection convert it, and MarshalBinary encodes it as MarshalKeys
+// does.
+//
+// The zero KeySet is empty and ready to use. A KeySet is not safe for
+// concurrent use while keys are being added.
+type KeySet struct {
+	zooms [MaxZoom + 1]*bitmap
+}
+
+// KeySetFromKeys returns a set holding keys. An error is returned if any key
+// is invalid.
+func KeySetFromKeys(keys []QuadKey) (*KeySet, error) {
+	s := &KeySet{}
+	for _, key := range keys {
+		if err := s.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add inserts the key into the set. An error is returned if the key is
+// invalid.
+func (s *KeySet) Add(key QuadKey) error {
+	if err := key.Valid(); err != nil {
+		return err
+	}
+	z := key.Z()
+	if s.zooms[z] == nil {
+		s.zooms[z] = &bitmap{}
+	}
+	s.zooms[z].add(key.Morton())
+	return nil
+}
+
+// Contains reports whether the key is in the set. Only the key itself
+// matches, not its ancestors or descendants.
+func (s *KeySet) Contains(key QuadKey) bool {
+	if key.Valid() != nil {
+		return false
+	}
+	b := s.zooms[key.Z()]
+	return b != nil && b.contains(key.Morton())
+}
+
+// Len returns the number of keys in the set.
+func (s *KeySet) Len() int {
+	n := 0
+	for _, b := range s.zooms {
+		if b != nil {
+			n += b.len()
+		}
+	}
+	return n
+}
+
+// Union returns a new set holding the keys in s or other.
+func (s *KeySet) Union(other *KeySet) *KeySet {
+	out := &KeySet{}
+	for z := range out.zooms {
+		out.zooms[z] = unionBitmaps(s.zooms[z], other.zooms[z])
+	}
+	return out
+}
+
+// Intersect returns a new set holding the keys in both s and other.
+func (s *KeySet) Intersect(other *KeySet) *KeySet {
+	out := &KeySet{}
+	for z := range out.zooms {
+		out.zooms[z] = intersectBitmaps(s.zooms[z], other.zooms[z])
+	}
+	return out
+}
+
+// Keys returns the keys in the set, sorted in quadkey order.
+func (s *KeySet) Keys() []QuadKey {
+	out := make([]QuadKey, 0, s.Len())
+	for z, b := range s.zooms {
+		if b == nil {
+			continue
+		}
+		for i, hi := range b.highs {
+			b.chunks[i].each(func(lo uint16) {
+				out = append(out, FromMorton(hi<<16|uint64(lo), z))
+			})
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Covers reports whether key or one of its ancestors is in the set, like the
+// Covers function on a sorted slice.
+func (s *KeySet) Covers(key QuadKey) bool {
+	if key.Valid() != nil {
+		return false
+	}
+	for z := 1; z <= key.Z(); z++ {
+		if s.Contains(key[:z]) {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversDescendant reports whether key or one of its descendants is in the
+// set. Each deeper zoom costs one range lookup over the key's Morton codes.
+func (s *KeySet) CoversDescendant(key QuadKey) bool {
+	if key.Valid() != nil {
+		return false
+	}
+	code := key.Morton()
+	for z := key.Z(); z <= MaxZoom; z++ {
+		shift := 2 * (z - key.Z())
+		lo := code << shift
+		if b := s.zooms[z]; b != nil && b.any(lo, lo|(1<<shift-1)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compact returns the smallest set of keys covering the set's tiles, as
+// Compact does for a slice.
+func (s *KeySet) Compact() []QuadKey {
+	keys, _ := Compact(s.Keys()) // every key in the set is valid
+	return keys
+}
+
+// ToFeatureCollection returns the set's tiles as polygon features, in
+// quadkey order.
+func (s *KeySet) ToFeatureCollection() *geojson.FeatureCollection {
+	return ToFeatureCollection(s.Keys()...)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the MarshalKeys
+// format.
+func (s *KeySet) MarshalBinary() ([]byte, error) {
+	return MarshalKeys(s.Keys())
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the set's
+// keys with those decoded by UnmarshalKeys.
+func (s *KeySet) UnmarshalBinary(data []byte) error {
+	keys, err := UnmarshalKeys(data)
+	if err != nil {
+		return err
+	}
+	*s = KeySet{}
+	for _, key := range keys {
+		if err := s.Add(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// bitmap is a compressed set of uint64 values: chunks[i] holds the low 16
+// bits of the values whose high bits are highs[i], with highs ascending.
+type bitmap struct {
+	highs  []uint64
+	chunks []*chunk
+}
+
+// chunk holds up to 65536 16-bit values, as a sorted array while it has at
+// most arrayMax of them and as a bitmap of 1024 words after that.
+type chunk struct {
+	array []uint16
+	words []uint64
+	n     int
+}
+
+func (b *bitmap) add(v uint64) {
+	hi := v >> 16
+	i, ok := slices.BinarySearch(b.highs, hi)
+	if !ok {
+		b.highs = slices.Insert(b.highs, i, hi)
+		b.chunks = slices.Insert(b.chunks, i, &chunk{})
+	}
+	b.chunks[i].add(uint16(v))
+}
+
+func (b *bitmap) contains(v uint64) bool {
+	i, ok := slices.BinarySearch(b.highs, v>>16)
+	return ok && b.chunks[i].contains(uint16(v))
+}
+
+// any reports whether the bitmap holds a value in [lo, hi].
+func (b *bitmap) any(lo, hi uint64) bool {
+	i, _ := slices.BinarySearch(b.highs, lo>>16)
+	for ; i < len(b.highs) && b.highs[i] <= hi>>16; i++ {
+		from, to := uint16(0), uint16(0xffff)
+		if b.highs[i] == lo>>16 {
+			from = uint16(lo)
+		}
+		if b.highs[i] == hi>>16 {
+			to = uint16(hi)
+		}
+		if b.chunks[i].any(from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *bitmap) len() int {
+	n := 0
+	for _, c := range b.chunks {
+		n += c.n
+	}
+	return n
+}
+
+// unionBitmaps merges the chunks of a and b by their high bits. Either may
+// be nil; chunks only in one input are copied, so the result never shares
+// memory with them.
+func unionBitmaps(a, b *bitmap) *bitmap {
+	if a == nil {
+		a, b = b, a
+	}
+	if a == nil {
+		return nil
+	}
+	out := &bitmap{}
+	if b == nil {
+		b = &bitmap{}
+	}
+	i, j := 0, 0
+	for i < len(a.highs) || j < len(b.highs) {
+		switch {
+		case j == len(b.highs) || i < len(a.highs) && a.highs[i] < b.highs[j]:
+			out.highs = append(out.highs, a.highs[i])
+			out.chunks = append(out.chunks, a.chunks[i].clone())
+			i++
+		case i == len(a.highs) || b.highs[j] < a.highs[i]:
+			out.highs = append(out.highs, b.highs[j])
+			out.chunks = append(out.chunks, b.chunks[j].clone())
+			j++
+		default:
+			out.highs = append(out.highs, a.highs[i])
+			out.chunks = append(out.chunks, unionChunks(a.chunks[i], b.chunks[j]))
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// intersectBitmaps intersects the chunks a and b share, dropping chunks that
+// end up empty. It returns nil if the result is empty.
+func intersectBitmaps(a, b *bitmap) *bitmap {
+	if a == nil || b == nil {
+		return nil
+	}
+	out := &bitmap{}
+	i, j := 0, 0
+	for i < len(a.highs) && j < len(b.highs) {
+		switch {
+		case a.highs[i] < b.highs[j]:
+			i++
+		case b.highs[j] < a.highs[i]:
+			j++
+		default:
+			if c := intersectChunks(a.chunks[i], b.chunks[j]); c.n > 0 {
+				out.highs = append(out.highs, a.highs[i])
+				out.chunks = append(out.chunks, c)
+			}
+			i++
+			j++
+		}
+	}
+	if len(out.highs) == 0 {
+		return nil
+	}
+	return out
+}
+
+func (c *chunk) add(lo uint16) {
+	if c.words != nil {
+		w, bit := &c.words[lo/64], uint64(1)<<(lo%64)
+		if *w&bit == 0 {
+			*w |= bit
+			c.n++
+		}
+		return
+	}
+	i, ok := slices.BinarySearch(c.array, lo)
+	if ok {
+		return
+	}
+	c.array = slices.Insert(c.array, i, lo)
+	c.n++
+	if c.n > arrayMax {
+		c.toWords()
+	}
+}
+
+func (c *chunk) contains(lo uint16) bool {
+	if c.words != nil {
+		return c.words[lo/64]&(1<<(lo%64)) != 0
+	}
+	_, ok := slices.BinarySearch(c.array, lo)
+	return ok
+}
+
+// any reports whether the chunk holds a value in [from, to].
+func (c *chunk) any(from, to uint16) bool {
+	if c.words == nil {
+		i, _ := slices.BinarySearch(c.array, from)
+		return i < len(c.array) && c.array[i] <= to
+	}
+	for i := int(from) / 64; i <= int(to)/64; i++ {
+		w := c.words[i]
+		if i == int(from)/64 {
+			w &= ^uint64(0) << (from % 64)
+		}
+		if i == int(to)/64 {
+			w &= ^uint64(0) >> (63 - to%64)
+		}
+		if w != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// each calls fn with the chunk's values in ascending order.
+func (c *chunk) each(fn func(lo uint16)) {
+	if c.words == nil {
+		for _, lo := range c.array {
+			fn(lo)
+		}
+		return
+	}
+	for i, w := range c.words {
+		for w != 0 {
+			fn(uint16(i*64 + bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+}
+
+// clone returns a copy of the chunk.
+func (c *chunk) clone() *chunk {
+	return &chunk{array: slices.Clone(c.array), words: slices.Clone(c.words), n: c.n}
+}
+
+// toWords converts an array chunk to a bitmap chunk.
+func (c *chunk) toWords() {
+	c.words = make([]uint64, 1024)
+	for _, lo := range c.array {
+		c.words[lo/64] |= 1 << (lo % 64)
+	}
+	c.array = nil
+}
+
+// toArray converts a bitmap chunk back to an array chunk once it is sparse.
+func (c *chunk) toArray() {
+	array := make([]uint16, 0, c.n)
+	c.each(func(lo uint16) { array = append(array, lo) })
+	c.array, c.words = array, nil
+}
+
+func unionChunks(a, b *chunk) *chunk {
+	if a.words == nil && b.words == nil && a.n+b.n <= arrayMax {
+		return (&chunk{array: mergeArrays(a.array, b.array, false)}).counted()
+	}
+	out := &chunk{words: make([]uint64, 1024)}
+	for _, c := range []*chunk{a, b} {
+		if c.words != nil {
+			for i, w := range c.words {
+				out.words[i] |= w
+			}
+			continue
+		}
+		for _, lo := range c.array {
+			out.words[lo/64] |= 1 << (lo % 64)
+		}
+	}
+	out = out.counted()
+	if out.n <= arrayMax {
+		out.toArray()
+	}
+	return out
+}
+
+func intersectChunks(a, b *chunk) *chunk {
+	switch {
+	case a.words == nil && b.words == nil:
+		return (&chunk{array: mergeArrays(a.array, b.array, true)}).counted()
+	case a.words == nil || b.words == nil:
+		if a.words != nil {
+			a, b = b, a
+		}
+		out := &chunk{}
+		for _, lo := range a.array {
+			if b.contains(lo) {
+				out.array = append(out.array, lo)
+			}
+		}
+		return out.counted()
+	}
+	out := &chunk{words: make([]uint64, 1024)}
+	for i := range out.words {
+		out.words[i] = a.words[i] & b.words[i]
+	}
+	out = out.counted()
+	if out.n <= arrayMax {
+		out.toArray()
+	}
+	return out
+}
+
+// counted sets the chunk's value count from its contents and returns it.
+func (c *chunk) counted() *chunk {
+	if c.words == nil {
+		c.n = len(c.array)
+		return c
+	}
+	c.n = 0
+	for _, w := range c.words {
+		c.n += bits.OnesCount64(w)
+	}
+	return c
+}
+
+// mergeArrays returns the sorted union of the sorted arrays a and b, or
+// their intersection if both is set.
+func mergeArrays(a, b []uint16, both bool) []uint16 {
+	var out []uint16
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			if !both {
+				out = append(out, a[i])
+			}
+			i++
+		case b[j] < a[i]:
+			if !both {
+				out = append(out, b[j])
+			}
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	if !both {
+		out = append(out, a[i:]...)
+		out = append(out, b[j:]...)
+	}
+	return out
+}
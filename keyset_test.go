@@ -0,0 +1,216 @@
+package quadkey
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// keySetFixture returns a dense block of zoom 15 keys, which fills bitmap
+// chunks, plus sparse random keys at mixed zooms, which stay in arrays.
+func keySetFixture(t *testing.T, bound orb.Bound, seed int64) []QuadKey {
+	t.Helper()
+	keys := KeysInBound(bound, 15)
+	rng := rand.New(rand.NewSource(seed))
+	for range 2000 {
+		digits := make([]byte, 1+rng.Intn(MaxZoom))
+		for i := range digits {
+			digits[i] = '0' + byte(rng.Intn(4))
+		}
+		keys = append(keys, QuadKey(digits))
+	}
+	return keys
+}
+
+func sortedSet(keys map[QuadKey]bool) []QuadKey {
+	return slices.Sorted(maps.Keys(keys))
+}
+
+func TestKeySetMatchesMap(t *testing.T) {
+	keys := keySetFixture(t, orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{141.0, 37.0}}, 1)
+	s, err := KeySetFromKeys(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[QuadKey]bool{}
+	for _, k := range keys {
+		want[k] = true
+	}
+	if s.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", s.Len(), len(want))
+	}
+	if !slices.ContainsFunc(s.zooms[15].chunks, func(c *chunk) bool { return c.words != nil }) {
+		t.Fatal("dense block did not fill a bitmap chunk")
+	}
+	if got := s.Keys(); !slices.Equal(got, sortedSet(want)) {
+		t.Fatalf("Keys() returned %d keys, want the %d added in quadkey order", len(got), len(want))
+	}
+	for _, k := range keys {
+		if !s.Contains(k) {
+			t.Fatalf("set misses %q", k)
+		}
+		if p, err := k.Parent(); err == nil && s.Contains(p) != want[p] {
+			t.Fatalf("Contains(%q) = %v, want %v", p, s.Contains(p), want[p])
+		}
+	}
+}
+
+func TestKeySetUnionIntersect(t *testing.T) {
+	a := keySetFixture(t, orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{141.0, 37.0}}, 1)
+	b := keySetFixture(t, orb.Bound{Min: orb.Point{140.0, 36.0}, Max: orb.Point{142.0, 38.0}}, 2)
+	b = append(b, a[len(a)-500:]...) // shared sparse keys
+
+	sa, err := KeySetFromKeys(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sb, err := KeySetFromKeys(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inA, inB := map[QuadKey]bool{}, map[QuadKey]bool{}
+	for _, k := range a {
+		inA[k] = true
+	}
+	for _, k := range b {
+		inB[k] = true
+	}
+	union, both := maps.Clone(inA), map[QuadKey]bool{}
+	for k := range inB {
+		union[k] = true
+		if inA[k] {
+			both[k] = true
+		}
+	}
+
+	u := sa.Union(sb)
+	if got := u.Keys(); !slices.Equal(got, sortedSet(union)) {
+		t.Fatalf("Union returned %d keys, want %d", len(got), len(union))
+	}
+	i := sa.Intersect(sb)
+	if got := i.Keys(); !slices.Equal(got, sortedSet(both)) {
+		t.Fatalf("Intersect returned %d keys, want %d", len(got), len(both))
+	}
+	if len(both) == 0 {
+		t.Fatal("fixture has no shared keys")
+	}
+
+	// The results must not share memory with their inputs.
+	for _, k := range KeysInBound(orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{-9, -9}}, 14) {
+		if err := u.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := i.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sa.Len() != len(inA) || sb.Len() != len(inB) {
+		t.Fatalf("Add on a result changed its inputs: Len() = %d, %d, want %d, %d", sa.Len(), sb.Len(), len(inA), len(inB))
+	}
+}
+
+func TestKeySetZeroValue(t *testing.T) {
+	var s KeySet
+	if s.Contains("0") || s.Len() != 0 || len(s.Keys()) != 0 {
+		t.Fatal("zero KeySet is not empty")
+	}
+	if err := s.Add("0123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Add("0123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.Contains("0123") || s.Len() != 1 {
+		t.Fatalf("Contains = %v, Len() = %d after adding one key twice", s.Contains("0123"), s.Len())
+	}
+	var empty KeySet
+	if got := s.Union(&empty).Keys(); !slices.Equal(got, []QuadKey{"0123"}) {
+		t.Fatalf("Union with empty = %v", got)
+	}
+	if got := s.Intersect(&empty).Len(); got != 0 {
+		t.Fatalf("Intersect with empty has %d keys", got)
+	}
+}
+
+func TestKeySetInvalidKeys(t *testing.T) {
+	var s KeySet
+	for _, k := range []QuadKey{"", "0124", QuadKey(slices.Repeat([]byte("0"), MaxZoom+1))} {
+		if err := s.Add(k); err == nil {
+			t.Fatalf("Add(%q) accepted an invalid key", k)
+		}
+		if s.Contains(k) {
+			t.Fatalf("Contains(%q) = true", k)
+		}
+	}
+	if _, err := KeySetFromKeys([]QuadKey{"0", "x"}); err == nil {
+		t.Fatal("KeySetFromKeys accepted an invalid key")
+	}
+}
+
+func TestKeySetHierarchy(t *testing.T) {
+	keys := keySetFixture(t, orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{141.0, 37.0}}, 3)
+	s, err := KeySetFromKeys(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+
+	var probes []QuadKey
+	for _, k := range keys[len(keys)-2000:] {
+		for z := 1; z <= k.Z(); z += 3 {
+			probes = append(probes, k[:z], k[:z]+"3", k[:z]+"0")
+		}
+	}
+	probes = append(probes, FromLonLat(140.5, 36.5, 12), FromLonLat(140.5, 36.5, 20), FromLonLat(-60, -30, 8))
+	for _, k := range probes {
+		if k.Z() > MaxZoom {
+			continue
+		}
+		if got, want := s.Covers(k), Covers(sorted, k); got != want {
+			t.Fatalf("Covers(%q) = %v, want %v", k, got, want)
+		}
+		if got, want := s.CoversDescendant(k), CoversDescendant(sorted, k); got != want {
+			t.Fatalf("CoversDescendant(%q) = %v, want %v", k, got, want)
+		}
+	}
+
+	want, err := Compact(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Compact(); !slices.Equal(got, want) {
+		t.Fatalf("Compact returned %d keys, want %d", len(got), len(want))
+	}
+	if got := len(s.ToFeatureCollection().Features); got != s.Len() {
+		t.Fatalf("ToFeatureCollection has %d features, want %d", got, s.Len())
+	}
+}
+
+func TestKeySetMarshalBinary(t *testing.T) {
+	keys := keySetFixture(t, orb.Bound{Min: orb.Point{139.0, 35.0}, Max: orb.Point{141.0, 37.0}}, 4)
+	s, err := KeySetFromKeys(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got KeySet
+	if err := got.Add("0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got.Keys(), s.Keys()) {
+		t.Fatalf("round trip returned %d keys, want %d", got.Len(), s.Len())
+	}
+	if err := got.UnmarshalBinary([]byte{9}); err == nil {
+		t.Fatal("UnmarshalBinary accepted bad data")
+	}
+}
@@ -0,0 +1,55 @@
+package quadkey
+
+import (
+	"slices"
+
+	"github.com/paulmach/orb/maptile"
+)
+
+// --------------------------
+// orb/maptile interop
+// --------------------------
+
+// MapTile returns the key as an orb maptile.Tile, for use with orb's tile
+// utilities. Invalid keys return the zero Tile, which is maptile's zoom 0
+// world tile, so check Valid first when the key is untrusted.
+func (key QuadKey) MapTile() maptile.Tile {
+	x, y, z := key.XYZ()
+	if z < 0 {
+		return maptile.Tile{}
+	}
+	return maptile.New(uint32(x), uint32(y), maptile.Zoom(z))
+}
+
+// FromMapTile returns the key of an orb maptile.Tile, or "" if the tile is
+// the zoom 0 world tile, which has no quadkey, or lies outside its zoom.
+func FromMapTile(t maptile.Tile) QuadKey {
+	if t.Z < 1 || t.Z > MaxZoom || !t.Valid() {
+		return ""
+	}
+	return FromXYZ(int(t.X), int(t.Y), int(t.Z))
+}
+
+// ToMapTileSet returns the keys as a maptile.Set. Invalid keys are skipped.
+func ToMapTileSet(keys ...QuadKey) maptile.Set {
+	set := make(maptile.Set, len(keys))
+	for _, key := range keys {
+		if key.Valid() == nil {
+			set[key.MapTile()] = true
+		}
+	}
+	return set
+}
+
+// KeysFromMapTileSet returns the keys of the tiles in set, in quadkey order.
+// Tiles mapped to false and tiles FromMapTile rejects are skipped.
+func KeysFromMapTileSet(set maptile.Set) []QuadKey {
+	keys := make([]QuadKey, 0, len(set))
+	for t, ok := range set {
+		if key := FromMapTile(t); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
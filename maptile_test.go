@@ -0,0 +1,47 @@
+package quadkey
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb/maptile"
+)
+
+func TestMapTile(t *testing.T) {
+	key := FromXYZ(3639, 1612, 12)
+	tile := key.MapTile()
+	if tile != maptile.New(3639, 1612, 12) {
+		t.Fatalf("got %+v", tile)
+	}
+	if got := FromMapTile(tile); got != key {
+		t.Fatalf("FromMapTile: got %q, want %q", got, key)
+	}
+	// maptile derives the same quadkey.
+	if tile.Quadkey() != key.Morton() {
+		t.Fatalf("maptile quadkey %d, want %d", tile.Quadkey(), key.Morton())
+	}
+
+	if QuadKey("01a").MapTile() != (maptile.Tile{}) {
+		t.Fatalf("expected the zero tile for an invalid key")
+	}
+	for _, tile := range []maptile.Tile{{}, maptile.New(4, 0, 2), maptile.New(0, 0, MaxZoom+1)} {
+		if got := FromMapTile(tile); got != "" {
+			t.Fatalf("%+v: expected empty key, got %q", tile, got)
+		}
+	}
+}
+
+func TestMapTileSet(t *testing.T) {
+	keys := []QuadKey{"3", "0123", "012"}
+	set := ToMapTileSet(append(keys, "01a")...)
+	if len(set) != 3 {
+		t.Fatalf("expected 3 tiles, got %d", len(set))
+	}
+
+	set[maptile.New(0, 0, 5)] = false
+	set[maptile.Tile{}] = true
+	got := KeysFromMapTileSet(set)
+	if want := []QuadKey{"012", "0123", "3"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
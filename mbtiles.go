@@ -0,0 +1,74 @@
+package quadkey
+
+import (
+	"database/sql"
+	"io"
+	"strconv"
+)
+
+// --------------------------
+// MBTiles seed lists
+// --------------------------
+
+// Execer runs a statement. *sql.DB and *sql.Tx satisfy it, so seed lists can
+// be written with any SQLite driver without this package importing one.
+type Execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// mbtilesMapSchema creates the MBTiles map table and its lookup index.
+var mbtilesMapSchema = []string{
+	"CREATE TABLE IF NOT EXISTS map (zoom_level INTEGER, tile_column INTEGER, tile_row INTEGER, tile_id TEXT)",
+	"CREATE UNIQUE INDEX IF NOT EXISTS map_index ON map (zoom_level, tile_column, tile_row)",
+}
+
+// WriteMBTilesMap inserts the keys into the map table of an MBTiles
+// database, creating the table if needed, with each tile's TMS row and the
+// key as tile_id, so a tile seeder can fill the images table from it. Rows
+// already present are replaced. An error is returned if any key is invalid
+// or a statement fails; wrap the call in a transaction to make it atomic
+// and fast.
+func WriteMBTilesMap(db Execer, keys ...QuadKey) error {
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range mbtilesMapSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		x, y, z := key.TMS()
+		_, err := db.Exec("INSERT OR REPLACE INTO map (zoom_level, tile_column, tile_row, tile_id) VALUES (?, ?, ?, ?)", z, x, y, string(key))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSeedList writes one "z/x/y" line per key to w, in XYZ tile
+// coordinates, the manifest format tile seeders and URL templates take. An
+// error is returned if any key is invalid, in which case the lines before it
+// have been written.
+func WriteSeedList(w io.Writer, keys ...QuadKey) error {
+	line := make([]byte, 0, 32)
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return err
+		}
+		x, y, z := key.XYZ()
+		line = strconv.AppendInt(line[:0], int64(z), 10)
+		line = append(line, '/')
+		line = strconv.AppendInt(line, int64(x), 10)
+		line = append(line, '/')
+		line = strconv.AppendInt(line, int64(y), 10)
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
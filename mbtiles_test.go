@@ -0,0 +1,65 @@
+package quadkey
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordingExecer records statements and fails the one at failAt, if set.
+type recordingExecer struct {
+	queries []string
+	args    [][]any
+	failAt  int
+}
+
+func (e *recordingExecer) Exec(query string, args ...any) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.args = append(e.args, args)
+	if len(e.queries) == e.failAt {
+		return nil, errors.New("exec failed")
+	}
+	return nil, nil
+}
+
+func TestWriteMBTilesMap(t *testing.T) {
+	db := &recordingExecer{}
+	if err := WriteMBTilesMap(db, "0", "0231"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(db.queries) != 4 || !strings.HasPrefix(db.queries[0], "CREATE TABLE IF NOT EXISTS map") {
+		t.Fatalf("unexpected statements %q", db.queries)
+	}
+	// 0231 is XYZ tile (3, 6) at zoom 4, TMS row 16-1-6.
+	if want := []any{4, 3, 9, "0231"}; !slices.Equal(db.args[3], want) {
+		t.Fatalf("got args %v, want %v", db.args[3], want)
+	}
+	if want := []any{1, 0, 1, "0"}; !slices.Equal(db.args[2], want) {
+		t.Fatalf("got args %v, want %v", db.args[2], want)
+	}
+
+	db = &recordingExecer{}
+	if err := WriteMBTilesMap(db, "0", "01a"); err == nil || len(db.queries) != 0 {
+		t.Fatalf("expected an error before any statement, got %v after %d", err, len(db.queries))
+	}
+	db = &recordingExecer{failAt: 3}
+	if err := WriteMBTilesMap(db, "0", "1"); err == nil || len(db.queries) != 3 {
+		t.Fatalf("expected the failing insert to stop the write, got %v after %d", err, len(db.queries))
+	}
+}
+
+func TestWriteSeedList(t *testing.T) {
+	var b strings.Builder
+	if err := WriteSeedList(&b, "0", "0231", "13300211"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "1/0/0\n4/3/6\n8/227/100\n"; b.String() != want {
+		t.Fatalf("got %q, want %q", b.String(), want)
+	}
+	b.Reset()
+	if err := WriteSeedList(&b, "1", "4"); err == nil || b.String() != "1/1/0\n" {
+		t.Fatalf("got %q, err %v", b.String(), err)
+	}
+}
@@ -0,0 +1,228 @@
+package quadkey
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+// --------------------------
+// tile dimensions
+// --------------------------
+
+// AreaMeters2 returns the tile's surface area in square meters on a sphere of
+// radius orb.EarthRadius. Tiles of the same zoom shrink towards the poles, as
+// the Web Mercator projection stretches them.
+func (key QuadKey) AreaMeters2() float64 {
+	bound := key.Bound()
+	if bound == (orb.Bound{}) {
+		return 0
+	}
+
+	dLon := degToRad(bound.Right() - bound.Left())
+	return orb.EarthRadius * orb.EarthRadius * dLon *
+		(math.Sin(degToRad(bound.Top())) - math.Sin(degToRad(bound.Bottom())))
+}
+
+// WidthMeters returns the east-west extent of the tile in meters, measured
+// along the parallel through the tile's center.
+func (key QuadKey) WidthMeters() float64 {
+	bound := key.Bound()
+	if bound == (orb.Bound{}) {
+		return 0
+	}
+
+	lat := degToRad(key.Center().Lat())
+	return orb.EarthRadius * math.Cos(lat) * degToRad(bound.Right()-bound.Left())
+}
+
+// HeightMeters returns the north-south extent of the tile in meters, measured
+// along a meridian.
+func (key QuadKey) HeightMeters() float64 {
+	bound := key.Bound()
+	if bound == (orb.Bound{}) {
+		return 0
+	}
+	return orb.EarthRadius * degToRad(bound.Top()-bound.Bottom())
+}
+
+// --------------------------
+// ground resolution
+// --------------------------
+
+// tileSize is the width and height of a tile in pixels.
+const tileSize = 256
+
+// GroundResolution returns the meters per pixel of 256-pixel tiles at zoom,
+// measured along the parallel at lat. Latitudes beyond the Web Mercator
+// limit are clamped to it. See TileScheme.GroundResolution for other tile
+// sizes.
+func GroundResolution(lat float64, zoom int) float64 {
+	return TileScheme{}.GroundResolution(lat, zoom)
+}
+
+// MapScale returns the denominator N of the 1:N map scale of 256-pixel tiles
+// at zoom and lat on a screen of screenDPI dots per inch, as in the Bing
+// Maps TileSystem reference. It returns 0 if screenDPI is not positive.
+func MapScale(lat float64, zoom, screenDPI int) float64 {
+	return TileScheme{}.MapScale(lat, zoom, screenDPI)
+}
+
+// ZoomForResolution returns the shallowest zoom, from 0 to MaxZoom, whose
+// ground resolution at lat is at least as fine as metersPerPixel. It returns
+// -1 if metersPerPixel is not positive.
+func ZoomForResolution(metersPerPixel, lat float64) int {
+	return TileScheme{}.ZoomForResolution(metersPerPixel, lat)
+}
+
+// GroundResolution returns the meters per pixel of the scheme's tiles at
+// zoom, measured along the parallel at lat. 512-pixel tiles have half the
+// meters per pixel of 256-pixel ones, the resolution of 256-pixel tiles one
+// zoom deeper.
+func (s TileScheme) GroundResolution(lat float64, zoom int) float64 {
+	_, lat = normalize(0, lat)
+	return math.Cos(degToRad(lat)) * 2 * math.Pi * orb.EarthRadius / (float64(s.size()) * math.Exp2(float64(zoom)))
+}
+
+// MapScale is the package-level MapScale for the scheme's tiles.
+func (s TileScheme) MapScale(lat float64, zoom, screenDPI int) float64 {
+	if screenDPI <= 0 {
+		return 0
+	}
+	const metersPerInch = 0.0254
+	return s.GroundResolution(lat, zoom) * float64(screenDPI) / metersPerInch
+}
+
+// ZoomForResolution is the package-level ZoomForResolution for the scheme's
+// tiles.
+func (s TileScheme) ZoomForResolution(metersPerPixel, lat float64) int {
+	if !(metersPerPixel > 0) {
+		return -1
+	}
+
+	// Resolutions halve with every zoom; the tolerance keeps exact matches
+	// from rounding up to the next zoom.
+	z := math.Ceil(math.Log2(s.GroundResolution(lat, 0)/metersPerPixel) - 1e-9)
+	return int(math.Max(0, math.Min(z, MaxZoom)))
+}
+
+// --------------------------
+// distances
+// --------------------------
+
+// DistanceMeters returns the great-circle distance between the centers of the
+// two tiles. It returns -1 if either key is invalid.
+func (key QuadKey) DistanceMeters(other QuadKey) float64 {
+	if key.Valid() != nil || other.Valid() != nil {
+		return -1
+	}
+	return geo.DistanceHaversine(key.Center(), other.Center())
+}
+
+// BoundDistanceMeters returns the shortest great-circle distance between the
+// two tiles' bounds: 0 when they overlap or share an edge or corner (tiles on
+// either side of the antimeridian touch). It returns -1 if either key is
+// invalid.
+func (key QuadKey) BoundDistanceMeters(other QuadKey) float64 {
+	if key.Valid() != nil || other.Valid() != nil {
+		return -1
+	}
+
+	a, b := key.Bound(), other.Bound()
+	if lonOverlaps(a, b) && a.Bottom() <= b.Top() && b.Bottom() <= a.Top() {
+		return 0
+	}
+
+	// For disjoint lat/lon rectangles the closest pair of points always
+	// involves a corner of one of them.
+	d := math.Inf(1)
+	for _, c := range key.Corners() {
+		d = math.Min(d, distanceToBound(c, b))
+	}
+	for _, c := range other.Corners() {
+		d = math.Min(d, distanceToBound(c, a))
+	}
+	return d
+}
+
+// DistancePoint returns the great-circle distance in meters from p to the
+// nearest point of the tile's bound, or 0 if p lies inside it or on its
+// edge. It returns -1 if the key is invalid.
+func (key QuadKey) DistancePoint(p orb.Point) float64 {
+	if key.Valid() != nil {
+		return -1
+	}
+	return distanceToBound(p, key.Bound())
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+func degToRad(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+// lonDelta returns the absolute longitude difference between a and b in
+// degrees, going the short way around the antimeridian.
+func lonDelta(a, b float64) float64 {
+	d := math.Mod(b-a, 360)
+	if d < 0 {
+		d += 360
+	}
+	return math.Min(d, 360-d)
+}
+
+// lonWithin reports whether lon lies within the bound's longitude range,
+// treating longitudes 360 degrees apart as equal.
+func lonWithin(lon float64, b orb.Bound) bool {
+	d := math.Mod(lon-b.Left(), 360)
+	if d < 0 {
+		d += 360
+	}
+	return d <= b.Right()-b.Left()
+}
+
+func lonOverlaps(a, b orb.Bound) bool {
+	return lonWithin(a.Left(), b) || lonWithin(a.Right(), b) ||
+		lonWithin(b.Left(), a) || lonWithin(b.Right(), a)
+}
+
+// distanceToBound returns the great-circle distance in meters from p to the
+// nearest point of the lat/lon rectangle b, or 0 if p lies inside it.
+func distanceToBound(p orb.Point, b orb.Bound) float64 {
+	lon, lat := p.Lon(), p.Lat()
+	if lonWithin(lon, b) {
+		if lat >= b.Bottom() && lat <= b.Top() {
+			return 0
+		}
+		// Straight north or south along the meridian is the shortest way
+		// onto a parallel edge.
+		return geo.DistanceHaversine(p, orb.Point{lon, math.Max(b.Bottom(), math.Min(lat, b.Top()))})
+	}
+
+	// Outside the longitude range, the nearest point lies on the meridian
+	// edge that is closer in longitude.
+	edge := b.Left()
+	if lonDelta(lon, b.Right()) < lonDelta(lon, b.Left()) {
+		edge = b.Right()
+	}
+
+	d := math.Min(
+		geo.DistanceHaversine(p, orb.Point{edge, b.Bottom()}),
+		geo.DistanceHaversine(p, orb.Point{edge, b.Top()}),
+	)
+	if cosDLon := math.Cos(degToRad(lonDelta(lon, edge))); cosDLon > 0 {
+		// Foot of the perpendicular from p onto the edge's great circle.
+		foot := radToDeg(math.Atan(math.Tan(degToRad(lat)) / cosDLon))
+		if foot > b.Bottom() && foot < b.Top() {
+			d = math.Min(d, geo.DistanceHaversine(p, orb.Point{edge, foot}))
+		}
+	}
+	return d
+}
+
+func radToDeg(r float64) float64 {
+	return r * 180 / math.Pi
+}
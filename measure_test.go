@@ -0,0 +1,197 @@
+package quadkey
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Fatalf("%s: got %f, want %f (±%f)", name, got, want, tolerance)
+	}
+}
+
+func TestAreaMeters2SumsToMercatorWorld(t *testing.T) {
+	// The Web Mercator square covers the sphere between ±MERCATOR_MAX_LAT.
+	want := 4 * math.Pi * orb.EarthRadius * orb.EarthRadius * math.Sin(degToRad(MERCATOR_MAX_LAT))
+
+	total := 0.0
+	for key := range QuadKey("0").DescendantsAtZoom(3) {
+		total += key.AreaMeters2()
+	}
+	for _, root := range []QuadKey{"1", "2", "3"} {
+		total += root.AreaMeters2()
+	}
+	assertNear(t, "world area", total, want, want*1e-9)
+}
+
+func TestAreaMeters2ShrinksTowardsPoles(t *testing.T) {
+	equator := FromXYZ(8, 8, 4).AreaMeters2()
+	polar := FromXYZ(8, 0, 4).AreaMeters2()
+	if !(polar < equator) {
+		t.Fatalf("expected polar tile to be smaller: polar=%f equator=%f", polar, equator)
+	}
+}
+
+func TestWidthAndHeightMeters(t *testing.T) {
+	// A zoom 1 tile spans half of the equator in width.
+	key := FromXYZ(0, 0, 1)
+	lat := degToRad(key.Center().Lat())
+	assertNear(t, "width", key.WidthMeters(), math.Pi*orb.EarthRadius*math.Cos(lat), 1e-6)
+	assertNear(t, "height", key.HeightMeters(), orb.EarthRadius*degToRad(MERCATOR_MAX_LAT), 1)
+
+	// Near the equator a tile is roughly square on the ground.
+	eq := FromXYZ(128, 128, 8)
+	if r := eq.WidthMeters() / eq.HeightMeters(); math.Abs(r-1) > 0.01 {
+		t.Fatalf("equatorial tile aspect ratio: got %f", r)
+	}
+
+	// Width times height approximates the area for small tiles.
+	small := QuadKey("1330022113300221")
+	assertNear(t, "area", small.WidthMeters()*small.HeightMeters(), small.AreaMeters2(), small.AreaMeters2()*1e-6)
+}
+
+func TestDimensionsInvalidKey(t *testing.T) {
+	key := QuadKey("01a3")
+	if key.AreaMeters2() != 0 || key.WidthMeters() != 0 || key.HeightMeters() != 0 {
+		t.Fatalf("expected zero dimensions for invalid key")
+	}
+}
+
+func TestGroundResolution(t *testing.T) {
+	// The well-known equatorial resolution of zoom 0 256-pixel tiles.
+	assertNear(t, "zoom 0", GroundResolution(0, 0), 156543.03392804097, 1e-6)
+	assertNear(t, "zoom 10", GroundResolution(0, 10), 156543.03392804097/1024, 1e-9)
+	assertNear(t, "60 degrees", GroundResolution(60, 3), 156543.03392804097/8/2, 1e-6)
+	assertNear(t, "clamped", GroundResolution(89, 5), GroundResolution(MERCATOR_MAX_LAT, 5), 0)
+}
+
+func TestMapScale(t *testing.T) {
+	// Values from the Bing Maps TileSystem table, at the equator and 96 dpi.
+	assertNear(t, "zoom 1", MapScale(0, 1, 96), 295829355.45, 0.01)
+	assertNear(t, "zoom 10", MapScale(0, 10, 96), 577791.71, 0.01)
+	assertNear(t, "dpi", MapScale(0, 10, 192), 2*MapScale(0, 10, 96), 1e-6)
+	assertNear(t, "no dpi", MapScale(0, 10, 0), 0, 0)
+}
+
+func TestZoomForResolution(t *testing.T) {
+	for _, lat := range []float64{0, 35.6, -60} {
+		for z := 0; z <= 20; z++ {
+			res := GroundResolution(lat, z)
+			assertEqualInt(t, "exact", ZoomForResolution(res, lat), z)
+			// Slightly coarser still fits at z; slightly finer needs z+1.
+			assertEqualInt(t, "coarser", ZoomForResolution(res*1.01, lat), z)
+			assertEqualInt(t, "finer", ZoomForResolution(res*0.99, lat), z+1)
+		}
+	}
+
+	assertEqualInt(t, "huge", ZoomForResolution(1e9, 0), 0)
+	assertEqualInt(t, "tiny", ZoomForResolution(1e-12, 0), MaxZoom)
+	assertEqualInt(t, "zero", ZoomForResolution(0, 0), -1)
+	assertEqualInt(t, "nan", ZoomForResolution(math.NaN(), 0), -1)
+}
+
+func TestDistanceMeters(t *testing.T) {
+	a := QuadKey("13300221")
+	if d := a.DistanceMeters(a); d != 0 {
+		t.Fatalf("distance to self: got %f", d)
+	}
+
+	b := FromXYZ(0, 0, 1)
+	c := FromXYZ(1, 0, 1)
+	want := geo.DistanceHaversine(b.Center(), c.Center())
+	assertNear(t, "distance", b.DistanceMeters(c), want, 1e-6)
+	assertNear(t, "symmetric", c.DistanceMeters(b), want, 1e-6)
+
+	if d := a.DistanceMeters("01a3"); d != -1 {
+		t.Fatalf("expected -1 for invalid key, got %f", d)
+	}
+}
+
+func TestBoundDistanceMeters(t *testing.T) {
+	key := FromXYZ(10, 10, 5)
+
+	// Neighbors and the key itself touch.
+	for _, n := range append(key.Neighbors(), key) {
+		if d := key.BoundDistanceMeters(n); d != 0 {
+			t.Fatalf("distance to touching tile %q: got %f", n, d)
+		}
+	}
+
+	// Tiles on both sides of the antimeridian touch.
+	if d := FromXYZ(0, 10, 5).BoundDistanceMeters(FromXYZ(31, 10, 5)); d != 0 {
+		t.Fatalf("distance across antimeridian: got %f", d)
+	}
+
+	// Two rows apart in the same column: the gap is one tile height.
+	south := FromXYZ(10, 12, 5)
+	gap := FromXYZ(10, 11, 5)
+	assertNear(t, "vertical gap", key.BoundDistanceMeters(south), gap.HeightMeters(), 1e-3)
+
+	// The bound distance never exceeds the center distance.
+	far := FromXYZ(20, 3, 5)
+	if bd, cd := key.BoundDistanceMeters(far), key.DistanceMeters(far); !(bd > 0 && bd < cd) {
+		t.Fatalf("bound distance %f should be positive and below center distance %f", bd, cd)
+	}
+
+	if d := key.BoundDistanceMeters("01a3"); d != -1 {
+		t.Fatalf("expected -1 for invalid key, got %f", d)
+	}
+}
+
+func TestDistancePoint(t *testing.T) {
+	key := QuadKey("1")
+	for _, p := range []orb.Point{{90, 40}, {0, 0}, {180, 85}} {
+		if d := key.DistancePoint(p); d != 0 {
+			t.Fatalf("point %v in or on the tile: got %f", p, d)
+		}
+	}
+	// One degree south of the equator, straight down from the south edge.
+	assertNear(t, "south of the tile", key.DistancePoint(orb.Point{90, -1}), degToRad(1)*orb.EarthRadius, 1e-6)
+	// Across the antimeridian, one degree west of the east edge at 180.
+	assertNear(t, "across the antimeridian", key.DistancePoint(orb.Point{-179, 0}), degToRad(1)*orb.EarthRadius, 1e-6)
+
+	if d := QuadKey("01a").DistancePoint(orb.Point{0, 0}); d != -1 {
+		t.Fatalf("expected -1 for invalid key, got %f", d)
+	}
+}
+
+func TestDistanceToBound(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{10, -5}, Max: orb.Point{20, 5}}
+
+	if d := distanceToBound(orb.Point{15, 0}, b); d != 0 {
+		t.Fatalf("inside point: got %f", d)
+	}
+
+	// Due east of the bound at the equator the nearest point is on the
+	// east edge at the same latitude.
+	assertNear(t, "east", distanceToBound(orb.Point{25, 0}, b), geo.DistanceHaversine(orb.Point{25, 0}, orb.Point{20, 0}), 1e-6)
+
+	// Due north, the nearest point is straight down the meridian.
+	assertNear(t, "north", distanceToBound(orb.Point{12, 10}, b), geo.DistanceHaversine(orb.Point{12, 10}, orb.Point{12, 5}), 1e-6)
+
+	// Wrapping: a point just west of the antimeridian is close to a bound
+	// just east of it.
+	wrap := orb.Bound{Min: orb.Point{-180, -1}, Max: orb.Point{-179, 1}}
+	assertNear(t, "wrap", distanceToBound(orb.Point{179.5, 0}, wrap), geo.DistanceHaversine(orb.Point{179.5, 0}, orb.Point{180, 0}), 1e-6)
+
+	// Brute force the nearest point along the edges for an off-axis point.
+	p := orb.Point{30, 40}
+	best := math.Inf(1)
+	for i := 0; i <= 10000; i++ {
+		f := float64(i) / 10000
+		for _, q := range []orb.Point{
+			{10 + 10*f, -5}, {10 + 10*f, 5}, {10, -5 + 10*f}, {20, -5 + 10*f},
+		} {
+			best = math.Min(best, geo.DistanceHaversine(p, q))
+		}
+	}
+	got := distanceToBound(p, b)
+	if got > best+1e-6 || got < best-50 {
+		t.Fatalf("off-axis: got %f, brute force %f", got, best)
+	}
+}
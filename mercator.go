@@ -0,0 +1,43 @@
+package quadkey
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// Web Mercator meters
+// --------------------------
+
+// mercatorExtent is half the width of the Web Mercator (EPSG:3857) plane in
+// meters: the projected x of longitude 180.
+const mercatorExtent = math.Pi * orb.EarthRadius
+
+// BoundMercator returns the tile's extent in Web Mercator (EPSG:3857) meters,
+// for raster warping and WMS bbox parameters. Invalid keys return an empty
+// bound.
+func (key QuadKey) BoundMercator() orb.Bound {
+	x, y, z := key.tileXY()
+	if z < 0 {
+		return orb.Bound{}
+	}
+	span := 2 * mercatorExtent / math.Exp2(float64(z))
+	return orb.Bound{
+		Min: orb.Point{-mercatorExtent + float64(x)*span, mercatorExtent - float64(y+1)*span},
+		Max: orb.Point{-mercatorExtent + float64(x+1)*span, mercatorExtent - float64(y)*span},
+	}
+}
+
+// FromMercatorXY returns the key at zoom of the tile holding the Web
+// Mercator point (mx, my), in meters. Points beyond the plane are clamped to
+// its edge tiles.
+func FromMercatorXY(mx, my float64, zoom int) QuadKey {
+	n := math.Exp2(float64(zoom))
+	clampTile := func(v float64) int {
+		return int(math.Max(0, math.Min(math.Floor(v*n), n-1)))
+	}
+	x := clampTile((mx + mercatorExtent) / (2 * mercatorExtent))
+	y := clampTile((mercatorExtent - my) / (2 * mercatorExtent))
+	return FromXYZ(x, y, zoom)
+}
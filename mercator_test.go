@@ -0,0 +1,46 @@
+package quadkey
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestBoundMercator(t *testing.T) {
+	b := QuadKey("1").BoundMercator()
+	assertNear(t, "min x", b.Min[0], 0, 1e-6)
+	assertNear(t, "min y", b.Min[1], 0, 1e-6)
+	assertNear(t, "max x", b.Max[0], 20037508.342789244, 1e-6)
+	assertNear(t, "max y", b.Max[1], 20037508.342789244, 1e-6)
+
+	// The projected corners of the lon/lat bound match.
+	key := FromLonLat(139.767125, 35.681236, 12)
+	b = key.BoundMercator()
+	ll := key.Bound()
+	assertNear(t, "west", b.Min[0], ll.Min[0]*mercatorExtent/180, 1e-6)
+	if got := FromMercatorXY((b.Min[0]+b.Max[0])/2, (b.Min[1]+b.Max[1])/2, 12); got != key {
+		t.Fatalf("FromMercatorXY of the center of %q: got %q", key, got)
+	}
+
+	if QuadKey("01a").BoundMercator() != (orb.Bound{}) {
+		t.Fatalf("expected empty bound for an invalid key")
+	}
+}
+
+func TestFromMercatorXY(t *testing.T) {
+	tests := []struct {
+		name   string
+		mx, my float64
+		zoom   int
+		want   QuadKey
+	}{
+		{"north-east of origin", 1, 1, 2, "12"},
+		{"clamped", -1e9, -1e9, 3, "222"},
+		{"tokyo", 15558875.6, 4257755.2, 8, FromLonLat(139.767125, 35.681236, 8)},
+	}
+	for _, tt := range tests {
+		if got := FromMercatorXY(tt.mx, tt.my, tt.zoom); got != tt.want {
+			t.Fatalf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
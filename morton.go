@@ -0,0 +1,67 @@
+package quadkey
+
+// --------------------------
+// Morton codes
+// --------------------------
+
+// Morton returns the key's Morton (Z-order) code: the bits of x on the even
+// positions and those of y on the odd positions, which is the same as the
+// key's digits read as a base-4 number. Codes of keys at the same zoom sort
+// in quadkey order, and the descendants of a key at a deeper zoom d have
+// codes from key.Morton()<<2(d-z) to ((key.Morton()+1)<<2(d-z))-1. The code
+// does not record the zoom. Invalid keys return 0.
+func (key QuadKey) Morton() uint64 {
+	x, y, z := key.tileXY()
+	if z < 1 {
+		return 0
+	}
+	return interleave(uint32(x), uint32(y))
+}
+
+// FromMorton returns the key at zoom z with the given Morton code. It returns
+// "" if z is not between 1 and MaxZoom or code has bits beyond the zoom's
+// 2z.
+func FromMorton(code uint64, z int) QuadKey {
+	if z < 1 || z > MaxZoom || (z < MaxZoom && code>>(2*z) != 0) {
+		return ""
+	}
+	x, y := deinterleave(code)
+	return tileKey(uint64(x), uint64(y), z)
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// interleave spreads x over the even bits and y over the odd bits of the
+// result, so every pair of bits is one quadkey digit.
+func interleave(x, y uint32) uint64 {
+	return spread(x) | spread(y)<<1
+}
+
+// deinterleave is the inverse of interleave.
+func deinterleave(v uint64) (x, y uint32) {
+	return compact(v), compact(v >> 1)
+}
+
+// spread moves bit i of v to bit 2i, using the usual magic-number shifts.
+func spread(v uint32) uint64 {
+	w := uint64(v)
+	w = (w | w<<16) & 0x0000ffff0000ffff
+	w = (w | w<<8) & 0x00ff00ff00ff00ff
+	w = (w | w<<4) & 0x0f0f0f0f0f0f0f0f
+	w = (w | w<<2) & 0x3333333333333333
+	w = (w | w<<1) & 0x5555555555555555
+	return w
+}
+
+// compact gathers the even bits of v, the inverse of spread.
+func compact(v uint64) uint32 {
+	v &= 0x5555555555555555
+	v = (v | v>>1) & 0x3333333333333333
+	v = (v | v>>2) & 0x0f0f0f0f0f0f0f0f
+	v = (v | v>>4) & 0x00ff00ff00ff00ff
+	v = (v | v>>8) & 0x0000ffff0000ffff
+	v = (v | v>>16) & 0x00000000ffffffff
+	return uint32(v)
+}
@@ -0,0 +1,72 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestMorton(t *testing.T) {
+	tests := []struct {
+		key  QuadKey
+		want uint64
+	}{
+		{"0", 0},
+		{"1", 1},
+		{"2", 2},
+		{"3", 3},
+		{"0123", 0b00011011},
+		{"3210", 0b11100100},
+		{"33333333333333333333333333333333", math.MaxUint64},
+	}
+	for _, tt := range tests {
+		if got := tt.key.Morton(); got != tt.want {
+			t.Fatalf("%q: got %b, want %b", tt.key, got, tt.want)
+		}
+		if got := FromMorton(tt.want, tt.key.Z()); got != tt.key {
+			t.Fatalf("FromMorton(%b, %d): got %q, want %q", tt.want, tt.key.Z(), got, tt.key)
+		}
+	}
+}
+
+func TestMortonMatchesZRangeAndOrder(t *testing.T) {
+	keys := slices.Collect(QuadKey("21").DescendantsAtZoom(6))
+	for i, k := range keys {
+		r, _ := k.ZRange(k.Z())
+		if k.Morton() != r.Start {
+			t.Fatalf("%q: morton %d, z-order value %d", k, k.Morton(), r.Start)
+		}
+		if i > 0 && keys[i-1].Morton() >= k.Morton() {
+			t.Fatalf("%q and %q are out of order", keys[i-1], k)
+		}
+	}
+}
+
+func TestInterleaveRoundTrip(t *testing.T) {
+	for _, c := range [][2]uint32{{0, 0}, {1, 0}, {0, 1}, {0xdeadbeef, 0x12345678}, {math.MaxUint32, 0}, {math.MaxUint32, math.MaxUint32}} {
+		v := interleave(c[0], c[1])
+		for i := range 32 {
+			if v>>(2*i)&1 != uint64(c[0]>>i&1) || v>>(2*i+1)&1 != uint64(c[1]>>i&1) {
+				t.Fatalf("interleave(%x, %x) = %x misplaces bit %d", c[0], c[1], v, i)
+			}
+		}
+		if x, y := deinterleave(v); x != c[0] || y != c[1] {
+			t.Fatalf("deinterleave(%x): got (%x, %x), want (%x, %x)", v, x, y, c[0], c[1])
+		}
+	}
+}
+
+func TestMortonInvalid(t *testing.T) {
+	if got := QuadKey("01a").Morton(); got != 0 {
+		t.Fatalf("invalid key: got %d", got)
+	}
+	if got := FromMorton(1<<8, 4); got != "" {
+		t.Fatalf("bits beyond the zoom: got %q", got)
+	}
+	if got := FromMorton(0, 0); got != "" {
+		t.Fatalf("zoom 0: got %q", got)
+	}
+	if got := FromMorton(0, 33); got != "" {
+		t.Fatalf("zoom 33: got %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package quadkey
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+// --------------------------
+// nearest tiles
+// --------------------------
+
+// NearestKeys returns the k tiles at zoom whose centers are closest to p by
+// great-circle distance, nearest first, with ties in quadkey order. The
+// search expands ring by ring from the tile holding p and stops as soon as
+// no tile further out can beat the k-th candidate, so its cost depends on k,
+// not on the size of the grid. Fewer than k keys are returned only when the
+// zoom has fewer tiles; a k below 1 or a zoom outside [1, MaxZoom] returns none.
+func NearestKeys(p orb.Point, zoom, k int) []QuadKey {
+	if k < 1 || zoom < 1 || zoom > MaxZoom {
+		return []QuadKey{}
+	}
+
+	type candidate struct {
+		key QuadKey
+		d   float64
+	}
+	byDistance := func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.d, b.d), cmp.Compare(a.key, b.key))
+	}
+
+	origin := FromPoint(p, zoom)
+	x, y, _ := origin.XYZ()
+	candidates := []candidate{}
+	for r := 0; ; r++ {
+		for _, key := range origin.Ring(r) {
+			candidates = append(candidates, candidate{key, geo.DistanceHaversine(p, key.Center())})
+		}
+		bound := diskLowerBound(p, x, y, zoom, r)
+		if math.IsInf(bound, 1) {
+			break
+		}
+		if len(candidates) >= k {
+			slices.SortFunc(candidates, byDistance)
+			candidates = candidates[:k]
+			if candidates[k-1].d <= bound {
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(candidates, byDistance)
+	keys := make([]QuadKey, 0, min(k, len(candidates)))
+	for _, c := range candidates[:min(k, len(candidates))] {
+		keys = append(keys, c.key)
+	}
+	return keys
+}
+
+// Nearest returns the key of keys whose tile is closest to p and the
+// great-circle distance in meters from p to that tile, 0 if p lies inside
+// it. keys must be sorted in quadkey order, as for Covers, and may mix
+// zooms. Rather than measuring every key, the grid of the finest zoom is
+// searched ring by ring outward from p with one membership lookup per tile;
+// only if the rings would visit more tiles than keys holds are all keys
+// measured instead. Ties go to the key first in quadkey order. With no valid
+// keys, Nearest returns "" and -1.
+func Nearest(keys []QuadKey, p orb.Point) (QuadKey, float64) {
+	zoom := 0
+	for _, key := range keys {
+		if key.Valid() == nil {
+			zoom = max(zoom, key.Z())
+		}
+	}
+	if zoom == 0 {
+		return "", -1
+	}
+
+	best, bestD := QuadKey(""), math.Inf(1)
+	consider := func(key QuadKey) {
+		d := key.DistancePoint(p)
+		if d < bestD || (d == bestD && key < best) {
+			best, bestD = key, d
+		}
+	}
+
+	origin := FromPoint(p, zoom)
+	x, y, z := origin.XYZ()
+	budget := 4*len(keys) + 64
+	for r, probed := 0, 0; ; r++ {
+		ring := origin.Ring(r)
+		for _, tile := range ring {
+			if key, ok := coveringKey(keys, tile); ok {
+				consider(key)
+			}
+		}
+		bound := diskLowerBound(p, x, y, z, r)
+		if bestD <= bound || math.IsInf(bound, 1) {
+			break
+		}
+		if probed += len(ring); probed > budget {
+			for _, key := range keys {
+				if key.Valid() == nil {
+					consider(key)
+				}
+			}
+			break
+		}
+	}
+	return best, bestD
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// diskLowerBound returns a lower bound, in meters, on the distance from p to
+// any point outside the rectangle of tiles within Chebyshev distance r of
+// tile (x, y) at zoom, the tile holding p. It is +Inf once the rectangle
+// spans the whole grid.
+func diskLowerBound(p orb.Point, x, y, zoom, r int) float64 {
+	lon, lat := normalize(p.Lon(), p.Lat())
+	n := 1 << zoom
+	nf := float64(n)
+	bound := math.Inf(1)
+
+	// Parallels: straight north or south along the meridian.
+	if y-r > 0 {
+		bound = math.Min(bound, orb.EarthRadius*degToRad(tileLat(float64(y-r), nf)-lat))
+	}
+	if y+r < n-1 {
+		bound = math.Min(bound, orb.EarthRadius*degToRad(lat-tileLat(float64(y+r+1), nf)))
+	}
+	// Meridians: the distance to a meridian's great circle, whose sine is
+	// sin(Δlon)·cos(lat), never exceeds the distance to the edge itself.
+	if 2*r+1 < n {
+		for _, edge := range []float64{tileLon(float64(x-r), nf), tileLon(float64(x+r+1), nf)} {
+			s := math.Abs(math.Sin(degToRad(lon-edge))) * math.Cos(degToRad(lat))
+			bound = math.Min(bound, orb.EarthRadius*math.Asin(math.Min(s, 1)))
+		}
+	}
+	return bound
+}
@@ -0,0 +1,96 @@
+package quadkey
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+// nearestByScan ranks every tile of a bound around p by center distance.
+func nearestByScan(p orb.Point, bound orb.Bound, zoom, k int) []QuadKey {
+	keys := KeysInBound(bound, zoom)
+	slices.SortFunc(keys, func(a, b QuadKey) int {
+		da, db := geo.DistanceHaversine(p, a.Center()), geo.DistanceHaversine(p, b.Center())
+		return cmp.Or(cmp.Compare(da, db), cmp.Compare(a, b))
+	})
+	return keys[:k]
+}
+
+func TestNearestKeys(t *testing.T) {
+	for _, tt := range []struct {
+		p    orb.Point
+		zoom int
+		k    int
+	}{
+		{orb.Point{139.7671, 35.6812}, 10, 1},
+		{orb.Point{139.7671, 35.6812}, 10, 9},
+		{orb.Point{139.7671, 35.6812}, 12, 30},
+		{orb.Point{10.001, 70.2}, 8, 12}, // tiles are far narrower than tall
+		{orb.Point{179.99, -20}, 6, 7},   // across the antimeridian
+	} {
+		got := NearestKeys(tt.p, tt.zoom, tt.k)
+		// A generous window around p holds the true k nearest tiles.
+		var window orb.Bound
+		if tt.p[0] > 179 {
+			window = orb.Bound{Min: orb.Point{-179.9, -40}, Max: orb.Point{179.9, 0}}
+		} else {
+			window = orb.Bound{Min: orb.Point{tt.p[0] - 10, tt.p[1] - 5}, Max: orb.Point{tt.p[0] + 10, tt.p[1] + 5}}
+		}
+		if want := nearestByScan(tt.p, window, tt.zoom, tt.k); !slices.Equal(got, want) {
+			t.Fatalf("%v at zoom %d, k %d: got %v, want %v", tt.p, tt.zoom, tt.k, got, want)
+		}
+	}
+
+	if got := NearestKeys(orb.Point{1, 1}, 4, 1); len(got) != 1 || got[0] != FromLonLat(1, 1, 4) {
+		t.Fatalf("k 1: got %v, want the containing tile", got)
+	}
+	if got := NearestKeys(orb.Point{0, 0}, 1, 10); len(got) != 4 {
+		t.Fatalf("zoom 1 holds 4 tiles, got %v", got)
+	}
+	if got := NearestKeys(orb.Point{0, 0}, 5, 0); len(got) != 0 {
+		t.Fatalf("k 0: got %v", got)
+	}
+	if got := NearestKeys(orb.Point{0, 0}, 33, 3); len(got) != 0 {
+		t.Fatalf("zoom 33: got %v", got)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for trial := range 50 {
+		// Sparse sets of mixed zooms, some large, some tiny.
+		n := 1 + rng.Intn(40)
+		if trial%5 == 0 {
+			n = 2000
+		}
+		keys := make([]QuadKey, n)
+		for i := range keys {
+			keys[i] = FromLonLat(rng.Float64()*360-180, rng.Float64()*160-80, 6+rng.Intn(8))
+		}
+		slices.Sort(keys)
+		p := orb.Point{rng.Float64()*360 - 180, rng.Float64()*160 - 80}
+
+		got, d := Nearest(keys, p)
+		want, wantD := QuadKey(""), -1.0
+		for _, key := range keys {
+			if kd := key.DistancePoint(p); wantD < 0 || kd < wantD || (kd == wantD && key < want) {
+				want, wantD = key, kd
+			}
+		}
+		if got != want || d != wantD {
+			t.Fatalf("trial %d: got %q at %.1f m, want %q at %.1f m", trial, got, d, want, wantD)
+		}
+	}
+
+	inside := FromLonLat(139.7671, 35.6812, 12)
+	if got, d := Nearest([]QuadKey{"0", inside[:5]}, orb.Point{139.7671, 35.6812}); got != inside[:5] || d != 0 {
+		t.Fatalf("containing key: got %q at %v", got, d)
+	}
+	if got, d := Nearest([]QuadKey{"01a"}, orb.Point{0, 0}); got != "" || d != -1 {
+		t.Fatalf("no valid keys: got %q, %v", got, d)
+	}
+}
@@ -0,0 +1,188 @@
+package quadkey
+
+import (
+	"fmt"
+	"slices"
+)
+
+// --------------------------
+// struct Direction
+// --------------------------
+
+// Direction selects one of the eight tiles adjacent to a key.
+type Direction int
+
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// directionOffsets holds the (dx, dy) tile offsets indexed by Direction.
+// y grows towards the south, matching XYZ tile coordinates.
+var directionOffsets = [...][2]int{
+	North:     {0, -1},
+	NorthEast: {1, -1},
+	East:      {1, 0},
+	SouthEast: {1, 1},
+	South:     {0, 1},
+	SouthWest: {-1, 1},
+	West:      {-1, 0},
+	NorthWest: {-1, -1},
+}
+
+var directionNames = [...]string{
+	North:     "north",
+	NorthEast: "north-east",
+	East:      "east",
+	SouthEast: "south-east",
+	South:     "south",
+	SouthWest: "south-west",
+	West:      "west",
+	NorthWest: "north-west",
+}
+
+func (dir Direction) Valid() error {
+	if dir < North || dir > NorthWest {
+		return fmt.Errorf("invalid direction: %d", int(dir))
+	}
+	return nil
+}
+
+func (dir Direction) String() string {
+	if dir.Valid() != nil {
+		return fmt.Sprintf("Direction(%d)", int(dir))
+	}
+	return directionNames[dir]
+}
+
+// --------------------------
+// neighbors
+// --------------------------
+
+// Neighbors returns the tiles surrounding the key at the same zoom level,
+// ordered row by row from the north-west to the south-east.
+// Columns wrap across the antimeridian; rows beyond the top or bottom of the
+// grid are skipped, so fewer than 8 keys are returned along the poles.
+func (key QuadKey) Neighbors() []QuadKey {
+	x, y, z := key.XYZ()
+	if x < 0 || y < 0 || z < 0 {
+		return []QuadKey{}
+	}
+
+	n := 1 << z
+	neighbors := make([]QuadKey, 0, 8)
+	for dy := -1; dy <= 1; dy++ {
+		ny := y + dy
+		if ny < 0 || ny >= n {
+			continue
+		}
+		for dx := -1; dx <= 1; dx++ {
+			neighbor := FromXYZ(wrapX(x+dx, n), ny, z)
+			// At low zooms the wrapped columns can fold back onto the key
+			// itself or onto each other.
+			if neighbor == key || slices.Contains(neighbors, neighbor) {
+				continue
+			}
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	return neighbors
+}
+
+// Neighbor returns the adjacent key in the given direction at the same zoom
+// level. Stepping east or west wraps across the antimeridian; stepping north
+// of the top row or south of the bottom row returns an error.
+func (key QuadKey) Neighbor(dir Direction) (QuadKey, error) {
+	if err := key.Valid(); err != nil {
+		return "", err
+	}
+	if err := dir.Valid(); err != nil {
+		return "", err
+	}
+
+	x, y, z := key.XYZ()
+	n := 1 << z
+	offset := directionOffsets[dir]
+	ny := y + offset[1]
+	if ny < 0 || ny >= n {
+		return "", fmt.Errorf("key has no %s neighbor: %s is on the edge of the grid", dir, key)
+	}
+	return FromXYZ(wrapX(x+offset[0], n), ny, z), nil
+}
+
+// Ring returns the tiles at exactly Chebyshev distance k from the key at the
+// same zoom level, ordered row by row from the north-west. Ring(0) is the key
+// itself. Columns wrap across the antimeridian and rows beyond the poles are
+// skipped.
+func (key QuadKey) Ring(k int) []QuadKey {
+	return key.chebyshev(k, true)
+}
+
+// Disk returns the tiles within Chebyshev distance k of the key at the same
+// zoom level, including the key itself, ordered row by row from the
+// north-west. Columns wrap across the antimeridian and rows beyond the poles
+// are skipped.
+func (key QuadKey) Disk(k int) []QuadKey {
+	return key.chebyshev(k, false)
+}
+
+func (key QuadKey) chebyshev(k int, ringOnly bool) []QuadKey {
+	x, y, z := key.XYZ()
+	if x < 0 || y < 0 || z < 0 || k < 0 {
+		return []QuadKey{}
+	}
+
+	n := 1 << z
+	// Collect each wrapped column once; once the span covers the whole
+	// grid, further offsets only revisit columns already taken.
+	columns := make([]int, 0, min(2*k+1, n))
+	for dx := -k; dx <= k && len(columns) < n; dx++ {
+		columns = append(columns, wrapX(x+dx, n))
+	}
+
+	keys := []QuadKey{}
+	for ny := max(y-k, 0); ny <= min(y+k, n-1); ny++ {
+		dy := abs(ny - y)
+		for _, nx := range columns {
+			d := max(columnDistance(x, nx, n), dy)
+			if d > k || (ringOnly && d != k) {
+				continue
+			}
+			keys = append(keys, FromXYZ(nx, ny, z))
+		}
+	}
+	return keys
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// wrapX maps a column index onto [0, n), wrapping across the antimeridian.
+func wrapX(x, n int) int {
+	x %= n
+	if x < 0 {
+		x += n
+	}
+	return x
+}
+
+// columnDistance is the number of columns between a and b, taking the
+// shorter way around the antimeridian.
+func columnDistance(a, b, n int) int {
+	d := wrapX(b-a, n)
+	return min(d, n-d)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,216 @@
+package quadkey
+
+import (
+	"sort"
+	"testing"
+)
+
+func keyStrings(keys []QuadKey) []string {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k.String())
+	}
+	sort.Strings(out)
+	return out
+}
+
+func assertSameKeys(t *testing.T, name string, got []QuadKey, want []QuadKey) {
+	t.Helper()
+	g, w := keyStrings(got), keyStrings(want)
+	if len(g) != len(w) {
+		t.Fatalf("%s: got %v, want %v", name, g, w)
+	}
+	for i := range w {
+		if g[i] != w[i] {
+			t.Fatalf("%s: got %v, want %v", name, g, w)
+		}
+	}
+}
+
+func TestNeighborsInterior(t *testing.T) {
+	key := FromXYZ(5, 5, 4)
+	want := []QuadKey{
+		FromXYZ(4, 4, 4), FromXYZ(5, 4, 4), FromXYZ(6, 4, 4),
+		FromXYZ(4, 5, 4), FromXYZ(6, 5, 4),
+		FromXYZ(4, 6, 4), FromXYZ(5, 6, 4), FromXYZ(6, 6, 4),
+	}
+	got := key.Neighbors()
+	if len(got) != 8 {
+		t.Fatalf("neighbors length: got %d, want 8", len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("neighbors[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNeighborsWrapAntimeridian(t *testing.T) {
+	key := FromXYZ(0, 3, 3)
+	got := key.Neighbors()
+	want := []QuadKey{
+		FromXYZ(7, 2, 3), FromXYZ(0, 2, 3), FromXYZ(1, 2, 3),
+		FromXYZ(7, 3, 3), FromXYZ(1, 3, 3),
+		FromXYZ(7, 4, 3), FromXYZ(0, 4, 3), FromXYZ(1, 4, 3),
+	}
+	assertSameKeys(t, "neighbors", got, want)
+}
+
+func TestNeighborsClampAtPoles(t *testing.T) {
+	top := FromXYZ(3, 0, 3).Neighbors()
+	if len(top) != 5 {
+		t.Fatalf("top row neighbors: got %d, want 5 (%v)", len(top), keyStrings(top))
+	}
+	bottom := FromXYZ(3, 7, 3).Neighbors()
+	if len(bottom) != 5 {
+		t.Fatalf("bottom row neighbors: got %d, want 5 (%v)", len(bottom), keyStrings(bottom))
+	}
+}
+
+func TestNeighborsLowZoomDeduplicates(t *testing.T) {
+	// At zoom 1 the east and west neighbors are the same tile.
+	got := QuadKey("0").Neighbors()
+	assertSameKeys(t, "neighbors", got, []QuadKey{"1", "2", "3"})
+}
+
+func TestNeighborsInvalidKey(t *testing.T) {
+	if got := QuadKey("01a3").Neighbors(); len(got) != 0 {
+		t.Fatalf("expected no neighbors for invalid key, got %v", got)
+	}
+}
+
+func TestNeighborDirections(t *testing.T) {
+	key := FromXYZ(5, 5, 4)
+	tests := []struct {
+		dir  Direction
+		x, y int
+	}{
+		{North, 5, 4},
+		{NorthEast, 6, 4},
+		{East, 6, 5},
+		{SouthEast, 6, 6},
+		{South, 5, 6},
+		{SouthWest, 4, 6},
+		{West, 4, 5},
+		{NorthWest, 4, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir.String(), func(t *testing.T) {
+			got, err := key.Neighbor(tt.dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := FromXYZ(tt.x, tt.y, 4); got != want {
+				t.Fatalf("neighbor: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNeighborWrapsAntimeridian(t *testing.T) {
+	got, err := FromXYZ(0, 2, 3).Neighbor(West)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := FromXYZ(7, 2, 3); got != want {
+		t.Fatalf("west of x=0: got %q, want %q", got, want)
+	}
+
+	got, err = FromXYZ(7, 2, 3).Neighbor(East)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := FromXYZ(0, 2, 3); got != want {
+		t.Fatalf("east of x=7: got %q, want %q", got, want)
+	}
+}
+
+func TestNeighborPoleEdge(t *testing.T) {
+	for _, dir := range []Direction{North, NorthEast, NorthWest} {
+		if _, err := FromXYZ(3, 0, 3).Neighbor(dir); err == nil {
+			t.Fatalf("expected error stepping %s from the top row", dir)
+		}
+	}
+	for _, dir := range []Direction{South, SouthEast, SouthWest} {
+		if _, err := FromXYZ(3, 7, 3).Neighbor(dir); err == nil {
+			t.Fatalf("expected error stepping %s from the bottom row", dir)
+		}
+	}
+}
+
+func TestNeighborInvalidInput(t *testing.T) {
+	if _, err := QuadKey("01a3").Neighbor(North); err == nil {
+		t.Fatalf("expected error for invalid key")
+	}
+	if _, err := QuadKey("0123").Neighbor(Direction(42)); err == nil {
+		t.Fatalf("expected error for invalid direction")
+	}
+}
+
+func TestRingAndDiskZero(t *testing.T) {
+	key := FromXYZ(5, 5, 4)
+	assertSameKeys(t, "ring(0)", key.Ring(0), []QuadKey{key})
+	assertSameKeys(t, "disk(0)", key.Disk(0), []QuadKey{key})
+}
+
+func TestRingOneMatchesNeighbors(t *testing.T) {
+	key := FromXYZ(0, 3, 3)
+	assertSameKeys(t, "ring(1)", key.Ring(1), key.Neighbors())
+}
+
+func TestRingAndDiskCounts(t *testing.T) {
+	key := FromXYZ(10, 10, 5)
+	for k := 0; k <= 3; k++ {
+		wantRing := 8 * k
+		if k == 0 {
+			wantRing = 1
+		}
+		assertEqualInt(t, "ring size", len(key.Ring(k)), wantRing)
+		assertEqualInt(t, "disk size", len(key.Disk(k)), (2*k+1)*(2*k+1))
+	}
+}
+
+func TestRingWrapsAntimeridian(t *testing.T) {
+	key := FromXYZ(0, 4, 3)
+	ring := key.Ring(2)
+	assertEqualInt(t, "ring size", len(ring), 16)
+	for _, k := range ring {
+		x, y, _ := k.XYZ()
+		d := max(columnDistance(0, x, 8), abs(y-4))
+		if d != 2 {
+			t.Fatalf("ring member %q at distance %d, want 2", k, d)
+		}
+	}
+}
+
+func TestDiskCoversWholeRowsAtLowZoom(t *testing.T) {
+	// At zoom 2 a radius of 2 spans every column, so no tile is repeated.
+	key := FromXYZ(1, 1, 2)
+	disk := key.Disk(2)
+	assertEqualInt(t, "disk size", len(disk), 16)
+
+	ring := key.Ring(2)
+	for _, k := range ring {
+		x, y, _ := k.XYZ()
+		if max(columnDistance(1, x, 4), abs(y-1)) != 2 {
+			t.Fatalf("ring member %q is not at distance 2", k)
+		}
+	}
+	assertEqualInt(t, "ring size", len(ring), 16-9)
+}
+
+func TestRingClampsAtPoles(t *testing.T) {
+	key := FromXYZ(4, 0, 4)
+	assertEqualInt(t, "ring size", len(key.Ring(1)), 5)
+	assertEqualInt(t, "disk size", len(key.Disk(1)), 6)
+}
+
+func TestRingInvalidInput(t *testing.T) {
+	if got := QuadKey("01a3").Ring(1); len(got) != 0 {
+		t.Fatalf("expected empty ring for invalid key, got %v", got)
+	}
+	if got := QuadKey("0123").Disk(-1); len(got) != 0 {
+		t.Fatalf("expected empty disk for negative k, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// struct TileScheme
+// --------------------------
+
+// TileScheme describes the pixel grid tiles are rendered on, for the pixel
+// conversions, ground resolution and viewport coverage that depend on the
+// tile size. Its pixel conversions follow the Bing Maps TileSystem
+// reference: at zoom z the world is Size << z pixels wide and tall, with
+// pixel (0, 0) at its north-west corner. The zero value uses 256-pixel tiles.
+//
+// 512-pixel tiles show at zoom z what 256-pixel tiles show at z+1, so a map
+// of 512-pixel tiles needs one zoom less for the same detail.
+type TileScheme struct {
+	// Size is the width and height of a tile in pixels; 0 means 256.
+	Size int
+}
+
+// size returns the tile size in pixels.
+func (s TileScheme) size() int {
+	if s.Size <= 0 {
+		return tileSize
+	}
+	return s.Size
+}
+
+// MapSize returns the width and height of the world, in pixels, at zoom.
+func (s TileScheme) MapSize(zoom int) int {
+	return s.size() << zoom
+}
+
+// LonLatToPixelXY returns the pixel holding the point at zoom. Latitudes are
+// clamped to the Web Mercator limit and longitudes to [-180, 180].
+func (s TileScheme) LonLatToPixelXY(lon, lat float64, zoom int) (px, py int) {
+	lat = math.Max(-MERCATOR_MAX_LAT, math.Min(lat, MERCATOR_MAX_LAT))
+	lon = math.Max(-180, math.Min(lon, 180))
+
+	x := (lon + 180) / 360
+	sinLat := math.Sin(degToRad(lat))
+	y := 0.5 - math.Log((1+sinLat)/(1-sinLat))/(4*math.Pi)
+
+	size := float64(s.MapSize(zoom))
+	clampPixel := func(v float64) int {
+		return int(math.Max(0, math.Min(v*size+0.5, size-1)))
+	}
+	return clampPixel(x), clampPixel(y)
+}
+
+// PixelXYToLonLat returns the point at the north-west corner of a pixel at
+// zoom. Pixels outside the map are clamped to its edge.
+func (s TileScheme) PixelXYToLonLat(px, py, zoom int) (lon, lat float64) {
+	size := float64(s.MapSize(zoom))
+	x := math.Max(0, math.Min(float64(px), size-1))/size - 0.5
+	y := 0.5 - math.Max(0, math.Min(float64(py), size-1))/size
+	return 360 * x, 90 - 360*math.Atan(math.Exp(-y*2*math.Pi))/math.Pi
+}
+
+// PixelXYToTileXY returns the tile holding a pixel.
+func (s TileScheme) PixelXYToTileXY(px, py int) (tx, ty int) {
+	return px / s.size(), py / s.size()
+}
+
+// TileXYToPixelXY returns the north-west pixel of a tile.
+func (s TileScheme) TileXYToPixelXY(tx, ty int) (px, py int) {
+	return tx * s.size(), ty * s.size()
+}
+
+// PixelInTile returns the position of a pixel within its tile, from (0, 0)
+// at the tile's north-west corner to (Size-1, Size-1).
+func (s TileScheme) PixelInTile(px, py int) (x, y int) {
+	return px % s.size(), py % s.size()
+}
+
+// ViewportKeys returns the tiles at zoom a screen of width by height pixels
+// centered on center shows, sorted in quadkey order. Viewports crossing the
+// antimeridian wrap around it; rows beyond the poles are dropped. Zooms
+// outside [1, MaxZoom] and empty viewports return no keys.
+func (s TileScheme) ViewportKeys(center orb.Point, zoom, width, height int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom || width <= 0 || height <= 0 {
+		return []QuadKey{}
+	}
+	lon, lat := normalize(center.Lon(), center.Lat())
+	n := 1 << zoom
+	size := float64(s.size())
+	cx := fracX(lon, float64(n)) * size
+	cy := fracY(lat, float64(n)) * size
+
+	// A viewport wider than the world shows every column once.
+	w := math.Min(float64(width), float64(n)*size)
+	minX := int(math.Floor((cx - w/2) / size))
+	maxX := int(math.Ceil((cx+w/2)/size)) - 1
+	minY := max(int(math.Floor((cy-float64(height)/2)/size)), 0)
+	maxY := min(int(math.Ceil((cy+float64(height)/2)/size))-1, n-1)
+
+	keys := make([]QuadKey, 0, (maxX-minX+1)*max(maxY-minY+1, 0))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX && x-minX < n; x++ {
+			keys = append(keys, FromXYZ((x%n+n)%n, y, zoom))
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestLonLatToPixelXY(t *testing.T) {
+	var s TileScheme
+	tests := []struct {
+		lon, lat float64
+		zoom     int
+		px, py   int
+	}{
+		{0, 0, 1, 256, 256},
+		{-180, 85.05112878, 3, 0, 0},
+		{180, -85.05112878, 3, 2047, 2047},
+		{-200, 90, 2, 0, 0},
+		{-122.3493, 47.6205, 10, 41980, 91537},
+	}
+	for _, tt := range tests {
+		px, py := s.LonLatToPixelXY(tt.lon, tt.lat, tt.zoom)
+		if px != tt.px || py != tt.py {
+			t.Fatalf("(%v, %v) at zoom %d: got (%d, %d), want (%d, %d)", tt.lon, tt.lat, tt.zoom, px, py, tt.px, tt.py)
+		}
+	}
+
+	if got := (TileScheme{Size: 512}).MapSize(1); got != 1024 {
+		t.Fatalf("512-pixel map size at zoom 1: got %d, want 1024", got)
+	}
+	if px, py := (TileScheme{Size: 512}).LonLatToPixelXY(0, 0, 1); px != 512 || py != 512 {
+		t.Fatalf("512-pixel tiles: got (%d, %d), want (512, 512)", px, py)
+	}
+}
+
+func TestPixelXYToLonLat(t *testing.T) {
+	var s TileScheme
+	lon, lat := s.PixelXYToLonLat(0, 0, 5)
+	if math.Abs(lon+180) > 1e-9 || math.Abs(lat-MERCATOR_MAX_LAT) > 1e-6 {
+		t.Fatalf("pixel (0, 0): got (%v, %v)", lon, lat)
+	}
+	px, py := s.LonLatToPixelXY(139.767125, 35.681236, 18)
+	lon, lat = s.PixelXYToLonLat(px, py, 18)
+	if math.Abs(lon-139.767125) > 1e-5 || math.Abs(lat-35.681236) > 1e-5 {
+		t.Fatalf("round trip: got (%v, %v)", lon, lat)
+	}
+}
+
+func TestPixelTiles(t *testing.T) {
+	var s TileScheme
+	px, py := s.LonLatToPixelXY(139.767125, 35.681236, 12)
+	tx, ty := s.PixelXYToTileXY(px, py)
+	if got := FromXYZ(tx, ty, 12); got != FromLonLat(139.767125, 35.681236, 12) {
+		t.Fatalf("pixel tile %q differs from FromLonLat", got)
+	}
+	nx, ny := s.TileXYToPixelXY(tx, ty)
+	ix, iy := s.PixelInTile(px, py)
+	if nx+ix != px || ny+iy != py || ix < 0 || ix > 255 || iy < 0 || iy > 255 {
+		t.Fatalf("tile origin (%d, %d) + offset (%d, %d) != pixel (%d, %d)", nx, ny, ix, iy, px, py)
+	}
+
+	s = TileScheme{Size: 512}
+	if tx, ty := s.PixelXYToTileXY(1023, 512); tx != 1 || ty != 1 {
+		t.Fatalf("512-pixel tiles: got tile (%d, %d)", tx, ty)
+	}
+	if x, y := s.PixelInTile(1023, 512); x != 511 || y != 0 {
+		t.Fatalf("512-pixel tiles: got offset (%d, %d)", x, y)
+	}
+}
+
+func TestViewportKeys(t *testing.T) {
+	center := QuadKey("1202").Center()
+
+	// A 256 by 256 viewport on a tile center shows its four children.
+	keys := TileScheme{}.ViewportKeys(center, 5, 256, 256)
+	if want := []QuadKey{"12020", "12021", "12022", "12023"}; !slices.Equal(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+
+	// 512-pixel tiles cover the same screen with four times fewer tiles.
+	keys = TileScheme{Size: 512}.ViewportKeys(center, 5, 512, 512)
+	if want := []QuadKey{"12020", "12021", "12022", "12023"}; !slices.Equal(keys, want) {
+		t.Fatalf("512-pixel tiles: got %v, want %v", keys, want)
+	}
+	if got := (TileScheme{Size: 512}).ViewportKeys(center, 4, 512, 512); len(got) != 1 || got[0] != "1202" {
+		t.Fatalf("512-pixel tiles at zoom 4: got %v", got)
+	}
+
+	// Across the antimeridian the columns wrap.
+	keys = TileScheme{}.ViewportKeys(orb.Point{180, 0}, 2, 256, 256)
+	if want := []QuadKey{"02", "13", "20", "31"}; !slices.Equal(keys, want) {
+		t.Fatalf("antimeridian: got %v, want %v", keys, want)
+	}
+
+	// A viewport larger than the world shows each tile once.
+	if got := (TileScheme{}).ViewportKeys(orb.Point{0, 0}, 1, 4000, 4000); !slices.Equal(got, []QuadKey{"0", "1", "2", "3"}) {
+		t.Fatalf("whole world: got %v", got)
+	}
+	if got := (TileScheme{}).ViewportKeys(center, 0, 256, 256); len(got) != 0 {
+		t.Fatalf("zoom 0: got %v", got)
+	}
+}
+
+func TestTileSchemeResolution(t *testing.T) {
+	s := TileScheme{Size: 512}
+	assertNear(t, "512 resolution", s.GroundResolution(35.6, 10), GroundResolution(35.6, 11), 1e-9)
+	assertNear(t, "512 scale", s.MapScale(35.6, 10, 96), MapScale(35.6, 11, 96), 1e-6)
+	assertEqualInt(t, "512 zoom", s.ZoomForResolution(GroundResolution(35.6, 11), 35.6), 10)
+}
@@ -0,0 +1,139 @@
+package quadkey
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// Open Location Code interop
+// --------------------------
+
+// Plus Code layout: up to five digit pairs refine latitude and longitude in
+// base 20, then up to five grid digits each split a cell into 5 rows and 4
+// columns. The separator follows the eighth digit.
+const (
+	plusAlphabet     = "23456789CFGHJMPQRVWX"
+	plusSeparator    = '+'
+	plusPadding      = '0'
+	plusSeparatorPos = 8
+	plusPairLen      = 10
+	plusGridLen      = 5
+	plusMaxLen       = plusPairLen + plusGridLen
+
+	// Integer steps of the finest cell, per degree.
+	plusLatPrecision = 8000 * 3125 // 8000 per degree after the pairs, 5^5 grid rows
+	plusLngPrecision = 8000 * 1024 // 4^5 grid columns
+)
+
+// PlusCode returns the full Plus Code, of length digits, of the cell holding
+// the key's center, such as "8FVC9G8F+6X" at length 10. Lengths below 8 are
+// padded with zeros, as in "8FVC0000+". Invalid keys and lengths other than
+// 2, 4, 6, 8 or 10 to 15 return "".
+func (key QuadKey) PlusCode(length int) string {
+	if key.Valid() != nil || length < 2 || length > plusMaxLen || (length < plusPairLen && length%2 != 0) {
+		return ""
+	}
+	c := key.Center()
+	lat := int64(math.Floor((c.Lat() + 90) * plusLatPrecision))
+	lng := int64(math.Floor((c.Lon() + 180) * plusLngPrecision))
+	lat = min(max(lat, 0), 180*plusLatPrecision-1)
+	lng = min(max(lng, 0), 360*plusLngPrecision-1)
+
+	// Work out the grid digits from the least significant end, then the
+	// pairs, and read the code back in order.
+	digits := make([]byte, plusMaxLen)
+	for i := plusMaxLen - 1; i >= plusPairLen; i-- {
+		digits[i] = plusAlphabet[lat%5*4+lng%4]
+		lat, lng = lat/5, lng/4
+	}
+	for i := plusPairLen - 2; i >= 0; i -= 2 {
+		digits[i], digits[i+1] = plusAlphabet[lat%20], plusAlphabet[lng%20]
+		lat, lng = lat/20, lng/20
+	}
+
+	var b strings.Builder
+	b.Grow(length + 2)
+	b.Write(digits[:min(length, plusSeparatorPos)])
+	for i := length; i < plusSeparatorPos; i++ {
+		b.WriteByte(plusPadding)
+	}
+	b.WriteByte(plusSeparator)
+	if length > plusSeparatorPos {
+		b.Write(digits[plusSeparatorPos:length])
+	}
+	return b.String()
+}
+
+// PlusCodeCover returns the keys at zoom covering the area of a full Plus
+// Code, as KeysInBound does for its bound. Codes are case insensitive;
+// short codes, which need a reference location, are rejected. An error is
+// returned for an invalid code or zoom.
+func PlusCodeCover(code string, zoom int) ([]QuadKey, error) {
+	if zoom < 1 || zoom > MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, MaxZoom)
+	}
+	b, err := plusCodeBound(code)
+	if err != nil {
+		return nil, err
+	}
+	return KeysInBound(b, zoom), nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// plusCodeBound decodes a full Plus Code to the lon/lat bound of its area.
+func plusCodeBound(code string) (orb.Bound, error) {
+	upper := strings.ToUpper(code)
+	sep := strings.IndexByte(upper, plusSeparator)
+	if sep < 0 {
+		return orb.Bound{}, fmt.Errorf("plus code %q has no separator", code)
+	}
+	if sep != plusSeparatorPos {
+		return orb.Bound{}, fmt.Errorf("plus code %q is not a full code", code)
+	}
+	digits := upper[:sep] + upper[sep+1:]
+	if len(digits) > plusMaxLen {
+		return orb.Bound{}, fmt.Errorf("plus code %q is too long", code)
+	}
+	if pad := strings.IndexByte(digits, plusPadding); pad >= 0 {
+		if pad == 0 || pad%2 != 0 || strings.TrimRight(digits[pad:], string(plusPadding)) != "" || len(digits) != plusSeparatorPos {
+			return orb.Bound{}, fmt.Errorf("plus code %q has invalid padding", code)
+		}
+		digits = digits[:pad]
+	}
+	if len(digits) == plusSeparatorPos+1 {
+		return orb.Bound{}, fmt.Errorf("plus code %q has a single digit after the separator", code)
+	}
+
+	lat, lng := -90.0, -180.0
+	latRes, lngRes := 400.0, 400.0
+	for i := 0; i < len(digits); i++ {
+		v := strings.IndexByte(plusAlphabet, digits[i])
+		if v < 0 {
+			return orb.Bound{}, fmt.Errorf("plus code %q contains invalid character %q", code, digits[i])
+		}
+		switch {
+		case i < plusPairLen && i%2 == 0:
+			latRes /= 20
+			lat += float64(v) * latRes
+		case i < plusPairLen:
+			lngRes /= 20
+			lng += float64(v) * lngRes
+		default:
+			latRes, lngRes = latRes/5, lngRes/4
+			lat += float64(v/4) * latRes
+			lng += float64(v%4) * lngRes
+		}
+	}
+	if lat >= 90 || lng >= 180 {
+		return orb.Bound{}, errors.New("plus code is outside the world")
+	}
+	return orb.Bound{Min: orb.Point{lng, lat}, Max: orb.Point{lng + lngRes, lat + latRes}}, nil
+}
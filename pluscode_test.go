@@ -0,0 +1,85 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestPlusCode(t *testing.T) {
+	tests := []struct {
+		lon, lat float64
+		length   int
+		want     string
+	}{
+		{2.775, 20.375, 6, "7FG49Q00+"},
+		{2.7821875, 20.3700625, 10, "7FG49QCJ+2V"},
+		{2.782234375, 20.3701125, 11, "7FG49QCJ+2VX"},
+		{8.0000625, 47.0000625, 10, "8FVC2222+22"},
+		{174.7859375, -41.2730625, 10, "4VCPPQGP+Q9"},
+		{-179.5, 0.5, 4, "62G20000+"},
+		{2.5, 20.5, 4, "7FG40000+"},
+	}
+	for _, tt := range tests {
+		key := FromLonLat(tt.lon, tt.lat, 30)
+		if got := key.PlusCode(tt.length); got != tt.want {
+			t.Fatalf("(%v, %v) at length %d: got %q, want %q", tt.lon, tt.lat, tt.length, got, tt.want)
+		}
+	}
+
+	key := FromLonLat(2.7821875, 20.3700625, 30)
+	for _, n := range []int{0, 1, 3, 9, 16} {
+		if got := key.PlusCode(n); got != "" {
+			t.Fatalf("length %d: expected empty code, got %q", n, got)
+		}
+	}
+	if got := QuadKey("01a").PlusCode(10); got != "" {
+		t.Fatalf("expected empty code for an invalid key, got %q", got)
+	}
+}
+
+func TestPlusCodeBound(t *testing.T) {
+	b, err := plusCodeBound("7fg49qcj+2v")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := orb.Bound{Min: orb.Point{2.782125, 20.37}, Max: orb.Point{2.78225, 20.370125}}
+	for i, v := range []float64{b.Min[0], b.Min[1], b.Max[0], b.Max[1]} {
+		w := []float64{want.Min[0], want.Min[1], want.Max[0], want.Max[1]}[i]
+		if math.Abs(v-w) > 1e-9 {
+			t.Fatalf("got %v, want %v", b, want)
+		}
+	}
+
+	for _, s := range []string{"", "7FG49QCJ2V", "9QCJ+2V", "7FG49QCJ+2", "7FG49QCJ+2VXGJXXX", "7FG4000+", "7F0000+", "7FG40000+2V", "7FG49QCA+2V", "FFG49QCJ+2V", "7XG49QCJ+2V"} {
+		if _, err := plusCodeBound(s); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
+
+func TestPlusCodeCover(t *testing.T) {
+	keys, err := PlusCodeCover("7FG49QCJ+2V", 18)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := plusCodeBound("7FG49QCJ+2V")
+	for _, p := range []orb.Point{{b.Min[0] + 1e-9, b.Max[1] - 1e-9}, {b.Max[0] - 1e-9, b.Min[1] + 1e-9}, b.Center()} {
+		if k := FromPoint(p, 18); !slices.Contains(keys, k) {
+			t.Fatalf("point %v (%q) is not covered", p, k)
+		}
+	}
+
+	// A tile's Plus Code covers its center.
+	tile := FromLonLat(139.767125, 35.681236, 14)
+	keys, _ = PlusCodeCover(tile.PlusCode(10), 14)
+	if !slices.Equal(keys, []QuadKey{tile}) {
+		t.Fatalf("got %v, want [%q]", keys, tile)
+	}
+
+	if _, err := PlusCodeCover("7FG49QCJ+2V", 0); err == nil {
+		t.Fatalf("expected error for zoom 0")
+	}
+}
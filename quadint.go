@@ -0,0 +1,172 @@
+package quadkey
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// struct QuadInt
+// --------------------------
+
+// MaxQuadIntZoom is the deepest zoom a QuadInt can hold.
+const MaxQuadIntZoom = 29
+
+// quadIntZoomBits is the number of low bits holding a QuadInt's zoom.
+const quadIntZoomBits = 5
+
+// QuadInt is a tile packed into a uint64: the zoom in the low 5 bits and the
+// key's digits, read as a base-4 number (the Z-order value), above them. It
+// mirrors the QuadKey API at a fixed 8 bytes per key with no allocations,
+// for zooms 1 to MaxQuadIntZoom. The zero value is invalid.
+//
+// QuadInts of the same zoom sort like their QuadKeys.
+type QuadInt uint64
+
+// QuadIntFromXYZ returns the QuadInt of tile (x, y) at zoom z. An error is
+// returned if z is out of range or the tile lies outside the grid.
+func QuadIntFromXYZ(x, y, z int) (QuadInt, error) {
+	if z < 1 || z > MaxQuadIntZoom {
+		return 0, fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxQuadIntZoom)
+	}
+	if x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		return 0, fmt.Errorf("tile (%d, %d) is outside zoom %d", x, y, z)
+	}
+	return QuadInt(interleave(uint32(x), uint32(y))<<quadIntZoomBits | uint64(z)), nil
+}
+
+// QuadInt converts the key to its packed form. An error is returned if the
+// key is invalid or deeper than MaxQuadIntZoom.
+func (key QuadKey) QuadInt() (QuadInt, error) {
+	if err := key.Valid(); err != nil {
+		return 0, err
+	}
+	if key.Z() > MaxQuadIntZoom {
+		return 0, fmt.Errorf("zoom %d is out of range [1, %d]", key.Z(), MaxQuadIntZoom)
+	}
+	return QuadInt(key.Morton()<<quadIntZoomBits | uint64(key.Z())), nil
+}
+
+// QuadKey converts back to the string form, or "" if q is invalid.
+func (q QuadInt) QuadKey() QuadKey {
+	if q.Valid() != nil {
+		return ""
+	}
+	z := q.Z()
+	v := q.value()
+	key := make([]byte, z)
+	for i := z - 1; i >= 0; i-- {
+		key[i] = '0' + byte(v&3)
+		v >>= 2
+	}
+	return QuadKey(key)
+}
+
+// String returns the quadkey digits, so QuadInts print like QuadKeys.
+func (q QuadInt) String() string {
+	return string(q.QuadKey())
+}
+
+// Valid reports whether q holds a zoom from 1 to MaxQuadIntZoom and no bits
+// beyond that zoom's digits.
+func (q QuadInt) Valid() error {
+	z := q.Z()
+	if z < 1 || z > MaxQuadIntZoom {
+		return fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxQuadIntZoom)
+	}
+	if q.value()>>(2*z) != 0 {
+		return errors.New("quadint has bits beyond its zoom")
+	}
+	return nil
+}
+
+// Z returns the zoom stored in q.
+func (q QuadInt) Z() int {
+	return int(q & (1<<quadIntZoomBits - 1))
+}
+
+// XYZ returns the tile coordinates, or -1, -1, -1 if q is invalid.
+func (q QuadInt) XYZ() (x, y, z int) {
+	if q.Valid() != nil {
+		return -1, -1, -1
+	}
+	ux, uy := deinterleave(q.value())
+	return int(ux), int(uy), q.Z()
+}
+
+// Parent returns the tile one zoom up. An error is returned if q is invalid
+// or at zoom 1.
+func (q QuadInt) Parent() (QuadInt, error) {
+	if err := q.Valid(); err != nil {
+		return 0, err
+	}
+	if q.Z() == 1 {
+		return 0, errors.New("key is root")
+	}
+	return QuadInt(q.value()>>2<<quadIntZoomBits | uint64(q.Z()-1)), nil
+}
+
+// Children returns the four tiles one zoom down, in digit order, or none if
+// q is invalid or already at MaxQuadIntZoom.
+func (q QuadInt) Children() []QuadInt {
+	if q.Valid() != nil || q.Z() == MaxQuadIntZoom {
+		return []QuadInt{}
+	}
+	children := make([]QuadInt, 0, 4)
+	for digit := range uint64(4) {
+		children = append(children, QuadInt((q.value()<<2|digit)<<quadIntZoomBits|uint64(q.Z()+1)))
+	}
+	return children
+}
+
+// Bound returns the tile's lon/lat bound, as QuadKey.Bound does, or an empty
+// bound if q is invalid.
+func (q QuadInt) Bound() orb.Bound {
+	return q.QuadKey().Bound()
+}
+
+// value returns the Z-order value above the zoom bits.
+func (q QuadInt) value() uint64 {
+	return uint64(q) >> quadIntZoomBits
+}
+
+// --------------------------
+// left-aligned int64 keys
+// --------------------------
+
+// ToQuadInt64 returns the key as a non-negative int64 holding its digits
+// left-aligned below the sign bit, two bits each, and its zoom in the low 5
+// bits, the numeric quadkey convention of several telemetry systems. Unlike
+// QuadInt, these integers sort exactly like the key strings, across zooms:
+// a key follows its ancestors and precedes its descendants. An error is
+// returned if the key is invalid or deeper than MaxQuadIntZoom.
+func (key QuadKey) ToQuadInt64() (int64, error) {
+	if err := key.Valid(); err != nil {
+		return 0, err
+	}
+	z := key.Z()
+	if z > MaxQuadIntZoom {
+		return 0, fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxQuadIntZoom)
+	}
+	return int64(key.Morton()<<(63-2*z) | uint64(z)), nil
+}
+
+// FromQuadInt64 decodes an integer written by ToQuadInt64. An error is
+// returned if v is negative, its zoom is out of range, or bits between the
+// digits and the zoom are set.
+func FromQuadInt64(v int64) (QuadKey, error) {
+	if v < 0 {
+		return "", fmt.Errorf("quadint64 %d is negative", v)
+	}
+	z := int(v & (1<<quadIntZoomBits - 1))
+	if z < 1 || z > MaxQuadIntZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxQuadIntZoom)
+	}
+	shift := 63 - 2*z
+	if uint64(v)&(1<<shift-1)&^(1<<quadIntZoomBits-1) != 0 {
+		return "", fmt.Errorf("quadint64 %d has bits beyond its zoom", v)
+	}
+	return FromMorton(uint64(v)>>shift, z), nil
+}
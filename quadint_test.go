@@ -0,0 +1,143 @@
+package quadkey
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQuadIntRoundTrip(t *testing.T) {
+	for _, key := range []QuadKey{"0", "3", "0123", "13300211", "2222222222222222", "31313131313131313131313131313"} {
+		q, err := key.QuadInt()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", key, err)
+		}
+		if err := q.Valid(); err != nil {
+			t.Fatalf("%q: invalid quadint: %v", key, err)
+		}
+		if got := q.QuadKey(); got != key {
+			t.Fatalf("round trip: got %q, want %q", got, key)
+		}
+		if q.String() != key.String() {
+			t.Fatalf("string: got %q, want %q", q.String(), key)
+		}
+
+		x, y, z := key.XYZ()
+		qx, qy, qz := q.XYZ()
+		if qx != x || qy != y || qz != z {
+			t.Fatalf("%q: XYZ got (%d,%d,%d), want (%d,%d,%d)", key, qx, qy, qz, x, y, z)
+		}
+		fromXYZ, err := QuadIntFromXYZ(x, y, z)
+		if err != nil || fromXYZ != q {
+			t.Fatalf("%q: QuadIntFromXYZ got %d, err %v, want %d", key, fromXYZ, err, q)
+		}
+		if q.Bound() != key.Bound() {
+			t.Fatalf("%q: bounds differ", key)
+		}
+	}
+}
+
+func TestQuadIntHierarchy(t *testing.T) {
+	key := QuadKey("0231")
+	q, _ := key.QuadInt()
+
+	parent, err := q.Parent()
+	if err != nil || parent.QuadKey() != "023" {
+		t.Fatalf("parent: got %q, err %v", parent, err)
+	}
+	root, _ := QuadKey("2").QuadInt()
+	if _, err := root.Parent(); err == nil {
+		t.Fatalf("expected error for the parent of a zoom 1 quadint")
+	}
+
+	children := []QuadKey{}
+	for _, c := range q.Children() {
+		children = append(children, c.QuadKey())
+	}
+	if !slices.Equal(children, key.Children()) {
+		t.Fatalf("children: got %v, want %v", children, key.Children())
+	}
+	deepest, _ := QuadIntFromXYZ(0, 0, MaxQuadIntZoom)
+	if len(deepest.Children()) != 0 {
+		t.Fatalf("expected no children at MaxQuadIntZoom")
+	}
+}
+
+func TestQuadIntOrder(t *testing.T) {
+	keys := slices.Collect(QuadKey("12").DescendantsAtZoom(5))
+	for i := 1; i < len(keys); i++ {
+		a, _ := keys[i-1].QuadInt()
+		b, _ := keys[i].QuadInt()
+		if a >= b {
+			t.Fatalf("%q and %q are out of order as quadints", keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestQuadIntInvalid(t *testing.T) {
+	for _, q := range []QuadInt{0, 30, 1<<40 | 3} {
+		if q.Valid() == nil {
+			t.Fatalf("%d: expected invalid", uint64(q))
+		}
+		if q.QuadKey() != "" || q.String() != "" {
+			t.Fatalf("%d: expected empty key", uint64(q))
+		}
+		if x, _, _ := q.XYZ(); x != -1 {
+			t.Fatalf("%d: expected -1 coordinates", uint64(q))
+		}
+		if len(q.Children()) != 0 {
+			t.Fatalf("%d: expected no children", uint64(q))
+		}
+	}
+
+	if _, err := QuadKey("012a").QuadInt(); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := QuadKey("000000000000000000000000000000").QuadInt(); err == nil {
+		t.Fatalf("expected error for zoom 30")
+	}
+	if _, err := QuadIntFromXYZ(4, 0, 2); err == nil {
+		t.Fatalf("expected error for a tile outside the grid")
+	}
+}
+
+func TestQuadInt64RoundTrip(t *testing.T) {
+	keys := []QuadKey{"0", "00", "0000", "01", "0123", "1", "2", "2301", "3", "31313131313131313131313131313", "33333"}
+	prev := int64(-1)
+	for _, key := range keys {
+		v, err := key.ToQuadInt64()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", key, err)
+		}
+		if v < 0 {
+			t.Fatalf("%q: negative value %d", key, v)
+		}
+		// The keys above are in string order, across zooms.
+		if v <= prev {
+			t.Fatalf("%q: %d does not sort after %d", key, v, prev)
+		}
+		prev = v
+
+		got, err := FromQuadInt64(v)
+		if err != nil || got != key {
+			t.Fatalf("round trip of %q: got %q, err %v", key, got, err)
+		}
+	}
+
+	if v, _ := QuadKey("1").ToQuadInt64(); v != 1<<61|1 {
+		t.Fatalf("layout: got %x", v)
+	}
+}
+
+func TestQuadInt64Invalid(t *testing.T) {
+	if _, err := QuadKey("01a").ToQuadInt64(); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := QuadKey("000000000000000000000000000000").ToQuadInt64(); err == nil {
+		t.Fatalf("expected error for zoom 30")
+	}
+	for _, v := range []int64{-1, 0, 1<<61 | 30, 1<<61 | 1<<40 | 2} {
+		if _, err := FromQuadInt64(v); err == nil {
+			t.Fatalf("%x: expected error", v)
+		}
+	}
+}
@@ -1,17 +1,69 @@
 package quadkey
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"math"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/clip"
 	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/project"
 )
 
 const MERCATOR_MAX_LAT = 85.05112878
 
+// MaxZoom is the deepest zoom a key can have: its 32 digits fill the 64 bits
+// of a Morton code or Hilbert index, and its tiles are under a centimeter
+// wide. Keys with more digits are invalid, and functions building keys
+// treat a zoom above MaxZoom as they do one below 1. Tile coordinates at
+// MaxZoom reach 2^32-1, so range checks on them are done in int64.
+const MaxZoom = 32
+
+// ErrTooManyTiles is returned, wrapped in a *TooManyTilesError, when an
+// operation would produce more tiles than the caller allowed.
+var ErrTooManyTiles = errors.New("too many tiles")
+
+// ErrEmptyKey is returned when a key has no digits.
+var ErrEmptyKey = errors.New("key is empty")
+
+// ErrInvalidDigit is returned, wrapped in an *InvalidDigitError, when a key
+// contains a character other than '0' to '3'.
+var ErrInvalidDigit = errors.New("key contains invalid digit")
+
+// InvalidDigitError reports the position and value of the first character of
+// a key that is not a quadkey digit.
+type InvalidDigitError struct {
+	Index int
+	Digit byte
+}
+
+func (e *InvalidDigitError) Error() string {
+	return fmt.Sprintf("key contains invalid digit at index %d: %q", e.Index, e.Digit)
+}
+
+func (e *InvalidDigitError) Unwrap() error {
+	return ErrInvalidDigit
+}
+
+// TooManyTilesError reports the number of tiles an operation would have
+// produced and the limit it exceeded.
+type TooManyTilesError struct {
+	Count int64
+	Limit int
+}
+
+func (e *TooManyTilesError) Error() string {
+	return fmt.Sprintf("too many tiles: %d exceeds the limit of %d", e.Count, e.Limit)
+}
+
+func (e *TooManyTilesError) Unwrap() error {
+	return ErrTooManyTiles
+}
+
 // --------------------------
 // struct QuadKey
 // --------------------------
@@ -22,16 +74,25 @@ func (key QuadKey) String() string {
 	return string(key)
 }
 
+// AppendTo appends the key's digits to dst and returns the extended buffer,
+// for writing keys into reused buffers without converting to []byte.
+func (key QuadKey) AppendTo(dst []byte) []byte {
+	return append(dst, key...)
+}
+
 func (key QuadKey) Valid() error {
 	if key == "" {
-		return errors.New("key is empty")
+		return ErrEmptyKey
+	}
+	if len(key) > MaxZoom {
+		return fmt.Errorf("zoom %d is out of range [1, %d]", len(key), MaxZoom)
 	}
 	for i := 0; i < len(key); i++ {
 		switch key[i] {
 		case '0', '1', '2', '3':
 			// ok
 		default:
-			return fmt.Errorf("key contains invalid digit at index %d: %q", i, key[i])
+			return &InvalidDigitError{Index: i, Digit: key[i]}
 		}
 	}
 	return nil
@@ -41,30 +102,27 @@ func (key QuadKey) Z() int {
 	return len(key)
 }
 
+// XYZ decodes the key into tile coordinates, or -1, -1, -1 if it is
+// invalid. Each digit's low bit is the next bit of x and its high bit the
+// next bit of y, so the key is decoded and validated in one pass. At zoom
+// 32, x and y reach 2^32-1, which fits an int only on 64-bit platforms; use
+// Morton or HilbertIndex for a portable encoding of keys that deep.
 func (key QuadKey) XYZ() (x, y, z int) {
-	if err := key.Valid(); err != nil {
+	ux, uy, z := key.tileXY()
+	if z < 0 {
 		return -1, -1, -1
 	}
+	return int(ux), int(uy), z
+}
 
-	x, y = 0, 0
-	z = key.Z()
-	for i := z; i > 0; i-- {
-		mask := 1 << (i - 1)
-		switch key[z-i] {
-		case '0':
-			// no-op
-		case '1':
-			x |= mask
-		case '2':
-			y |= mask
-		case '3':
-			x |= mask
-			y |= mask
-		default:
-			return -1, -1, -1
-		}
+// XYZChecked is XYZ, but reports why an invalid key cannot be decoded
+// instead of returning -1, -1, -1.
+func (key QuadKey) XYZChecked() (x, y, z int, err error) {
+	if err := key.Valid(); err != nil {
+		return -1, -1, -1, err
 	}
-	return
+	x, y, z = key.XYZ()
+	return x, y, z, nil
 }
 
 func (key QuadKey) Parent() (QuadKey, error) {
@@ -79,50 +137,208 @@ func (key QuadKey) Parent() (QuadKey, error) {
 	return QuadKey(parent), nil
 }
 
-func (key QuadKey) Children() []QuadKey {
+// AncestorAtZoom returns the key containing this one at zoom z, which must be
+// between 1 and the key's own zoom. Passing the key's zoom returns the key.
+func (key QuadKey) AncestorAtZoom(z int) (QuadKey, error) {
 	if err := key.Valid(); err != nil {
-		return []QuadKey{}
+		return "", err
+	}
+	if z < 1 || z > key.Z() {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, key.Z())
 	}
+	return key[:z], nil
+}
 
-	x, y, z := key.XYZ()
-	if x < 0 || y < 0 || z < 0 {
+// Children returns the four keys one zoom deeper that split this one, in
+// digit order. Keys at MaxZoom have no children and return nil.
+func (key QuadKey) Children() []QuadKey {
+	if err := key.Valid(); err != nil {
 		return []QuadKey{}
 	}
+	if key.Z() == MaxZoom {
+		return nil
+	}
 
 	// Child tiles at zoom z+1 are the 2x2 subdivision of the parent tile.
 	// The order corresponds to appending digits 0,1,2,3.
-	cx, cy := x<<1, y<<1
-	zz := z + 1
-	return []QuadKey{
-		FromXYZ(cx, cy, zz),     // 0
-		FromXYZ(cx+1, cy, zz),   // 1
-		FromXYZ(cx, cy+1, zz),   // 2
-		FromXYZ(cx+1, cy+1, zz), // 3
+	return []QuadKey{key + "0", key + "1", key + "2", key + "3"}
+}
+
+// ChildrenChecked is Children, but reports why an invalid key or a key at
+// MaxZoom has no children instead of returning an empty slice.
+func (key QuadKey) ChildrenChecked() ([]QuadKey, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
 	}
+	if key.Z() == MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", key.Z()+1, MaxZoom)
+	}
+	return key.Children(), nil
 }
 
-func (key QuadKey) Bound() orb.Bound {
+// DescendantsAtZoom yields every key contained in this one at zoom z, in
+// lexicographic (digit) order. The sequence is lazy: there are 4^(z-key.Z())
+// descendants, so callers should stream them rather than collect them.
+// Invalid keys, zooms coarser than the key and zooms beyond MaxZoom yield
+// nothing.
+func (key QuadKey) DescendantsAtZoom(z int) iter.Seq[QuadKey] {
+	return func(yield func(QuadKey) bool) {
+		if key.Valid() != nil || z < key.Z() || z > MaxZoom {
+			return
+		}
+
+		buf := make([]byte, z)
+		copy(buf, key)
+		var walk func(i int) bool
+		walk = func(i int) bool {
+			if i == z {
+				return yield(QuadKey(buf))
+			}
+			for digit := byte('0'); digit <= '3'; digit++ {
+				buf[i] = digit
+				if !walk(i + 1) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(key.Z())
+	}
+}
+
+// Siblings returns the other three keys that share the key's parent, in digit
+// order. Keys at zoom 1 are siblings of the other zoom 1 tiles.
+func (key QuadKey) Siblings() []QuadKey {
 	if err := key.Valid(); err != nil {
+		return []QuadKey{}
+	}
+
+	z := key.Z()
+	siblings := make([]QuadKey, 0, 3)
+	for _, digit := range []byte("0123") {
+		if key[z-1] == digit {
+			continue
+		}
+		siblings = append(siblings, key[:z-1]+QuadKey(digit))
+	}
+	return siblings
+}
+
+// IsAncestorOf reports whether the key strictly contains other, i.e. other is
+// at a deeper zoom and lies inside this tile. Invalid keys are never related.
+func (key QuadKey) IsAncestorOf(other QuadKey) bool {
+	return key.Relationship(other) == Ancestor
+}
+
+// IsDescendantOf reports whether the key lies strictly inside other.
+// Invalid keys are never related.
+func (key QuadKey) IsDescendantOf(other QuadKey) bool {
+	return key.Relationship(other) == Descendant
+}
+
+// Relationship describes how the key relates to other: the same tile, an
+// enclosing tile (Ancestor), an enclosed tile (Descendant) or neither.
+func (key QuadKey) Relationship(other QuadKey) Relationship {
+	if key.Valid() != nil || other.Valid() != nil {
+		return Disjoint
+	}
+	switch {
+	case key == other:
+		return Equal
+	case len(key) < len(other) && other[:len(key)] == key:
+		return Ancestor
+	case len(key) > len(other) && key[:len(other)] == other:
+		return Descendant
+	default:
+		return Disjoint
+	}
+}
+
+// Bound returns the tile's lon/lat extent, or an empty bound if the key is
+// invalid. Edges are computed from the exact integer tile coordinates, so
+// adjacent tiles, and a tile and its descendants, share bit-identical edge
+// coordinates and can be snapped together without a tolerance.
+func (key QuadKey) Bound() orb.Bound {
+	x, y, z := key.tileXY()
+	if z < 0 {
 		return orb.Bound{}
 	}
+	return tileBound(x, y, z)
+}
+
+// BoundChecked is Bound, but reports why an invalid key has no bound
+// instead of returning an empty one.
+func (key QuadKey) BoundChecked() (orb.Bound, error) {
+	if err := key.Valid(); err != nil {
+		return orb.Bound{}, err
+	}
+	return key.Bound(), nil
+}
 
+// Center returns the lon/lat of the tile's center in Web Mercator space. Away
+// from the equator it lies poleward of the average of the tile's edges.
+func (key QuadKey) Center() orb.Point {
+	x, y, z := key.tileXY()
+	if z < 0 {
+		return orb.Point{}
+	}
+
+	n := math.Pow(2.0, float64(z))
+	return orb.Point{
+		tileLon(float64(x)+0.5, n),
+		tileLat(float64(y)+0.5, n),
+	}
+}
+
+// ContainsPoint reports whether p falls in the tile. The check is done in tile
+// index space, so points on a shared edge belong to exactly the tile that
+// FromPoint assigns them to.
+func (key QuadKey) ContainsPoint(p orb.Point) bool {
+	if key.Valid() != nil {
+		return false
+	}
+	return FromPoint(p, key.Z()) == key
+}
+
+// IntersectsBound reports whether the tile overlaps bound. It uses the same
+// half-open tile index semantics as KeysInBound: the tile intersects bound
+// exactly when KeysInBound(bound, key.Z()) would return it.
+func (key QuadKey) IntersectsBound(bound orb.Bound) bool {
 	x, y, z := key.XYZ()
 	if x < 0 || y < 0 || z < 0 {
-		return orb.Bound{}
+		return false
 	}
+	minX, minY, maxX, maxY := tileRange(bound, z)
+	return minX <= x && x <= maxX && minY <= y && y <= maxY
+}
 
-	n := math.Pow(2.0, float64(z))
+// ContainsBound reports whether bound lies entirely within the tile, i.e.
+// KeysInBound(bound, key.Z()) would return only this tile.
+func (key QuadKey) ContainsBound(bound orb.Bound) bool {
+	x, y, z := key.XYZ()
+	if x < 0 || y < 0 || z < 0 {
+		return false
+	}
+	minX, minY, maxX, maxY := tileRange(bound, z)
+	return minX == x && maxX == x && minY == y && maxY == y
+}
 
-	west := float64(x)/n*360 - 180
-	east := float64(x+1)/n*360 - 180
-	north := math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi
-	south := math.Atan(math.Sinh(math.Pi*(1-2*float64(y+1)/n))) * 180 / math.Pi
+// Corners returns the tile's corner points in clockwise order starting at the
+// north-west corner: NW, NE, SE, SW. Invalid keys return zero points.
+func (key QuadKey) Corners() [4]orb.Point {
+	bound := key.Bound()
+	if bound == (orb.Bound{}) {
+		return [4]orb.Point{}
+	}
 
-	diagonal := orb.LineString{
-		{west, north},
-		{east, south},
+	west, south := bound.Left(), bound.Bottom()
+	east, north := bound.Right(), bound.Top()
+	return [4]orb.Point{
+		{west, north}, // NW
+		{east, north}, // NE
+		{east, south}, // SE
+		{west, south}, // SW
 	}
-	return diagonal.Bound()
 }
 
 func (key QuadKey) MarshalJSON() ([]byte, error) {
@@ -139,17 +355,208 @@ func (key *QuadKey) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText implements encoding.TextMarshaler, so keys can be written
+// wherever text encodings are honored, such as YAML and TOML values.
+func (key QuadKey) MarshalText() ([]byte, error) {
+	return []byte(key), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Unlike UnmarshalJSON it
+// validates the key, as FromKey does, so bad keys in configs and JSON object
+// keys are rejected at decode time.
+func (key *QuadKey) UnmarshalText(text []byte) error {
+	value, err := FromKey(string(text))
+	if err != nil {
+		return err
+	}
+	*key = value
+	return nil
+}
+
 func (key *QuadKey) ToPolygon() orb.Polygon {
 	bound := key.Bound()
 	return bound.ToPolygon()
 }
 
+// ToRing returns the tile outline as a closed ring, counter-clockwise from the
+// south-west corner. It is the same ring ToPolygon wraps.
+func (key QuadKey) ToRing() orb.Ring {
+	bound := key.Bound()
+	if bound == (orb.Bound{}) {
+		return orb.Ring{}
+	}
+	return bound.ToRing()
+}
+
+// EdgeLineString returns the tile outline as a closed LineString, following
+// the same points as ToRing.
+func (key QuadKey) EdgeLineString() orb.LineString {
+	return orb.LineString(key.ToRing())
+}
+
+// Clip returns the part of g inside the tile, or nil if none of it is, as
+// orb/clip computes it. g is not modified. Invalid keys return nil.
+func (key QuadKey) Clip(g orb.Geometry) orb.Geometry {
+	return key.ClipBuffered(g, 0)
+}
+
+// ClipBuffered is Clip against the tile grown by buffer pixels of a 256-pixel
+// tile on every side. Vector tile encoders keep such headroom, typically 64
+// pixels, so lines and polygon edges do not show seams where tiles meet. The
+// buffer is measured in Web Mercator, so it is the same on screen along all
+// four edges.
+func (key QuadKey) ClipBuffered(g orb.Geometry, buffer float64) orb.Geometry {
+	x, y, z := key.tileXY()
+	if g == nil || z < 0 {
+		return nil
+	}
+	n := math.Exp2(float64(z))
+	b := buffer / tileSize
+	box := orb.Bound{
+		Min: orb.Point{tileLon(float64(x)-b, n), tileLat(float64(y+1)+b, n)},
+		Max: orb.Point{tileLon(float64(x+1)+b, n), tileLat(float64(y)-b, n)},
+	}
+	return clip.Geometry(box, orb.Clone(g))
+}
+
+// ProjectToTile converts a lon/lat geometry into the tile's local integer
+// coordinates, with (0, 0) at the north-west corner and (extent, extent) at
+// the south-east one, as vector tile encoders expect (an extent of 4096 is
+// common). Points outside the tile map outside that range, so clip first,
+// or after with a buffer. g is not modified. Invalid keys and extents below
+// 1 return nil.
+func (key QuadKey) ProjectToTile(g orb.Geometry, extent int) orb.Geometry {
+	x, y, z := key.tileXY()
+	if g == nil || z < 0 || extent < 1 {
+		return nil
+	}
+	n := math.Exp2(float64(z))
+	e := float64(extent)
+	return project.Geometry(orb.Clone(g), func(p orb.Point) orb.Point {
+		lat := math.Max(-MERCATOR_MAX_LAT, math.Min(p.Lat(), MERCATOR_MAX_LAT))
+		return orb.Point{
+			math.Round((fracX(p.Lon(), n) - float64(x)) * e),
+			math.Round((fracY(lat, n) - float64(y)) * e),
+		}
+	})
+}
+
+// ProjectFromTile is the inverse of ProjectToTile, converting tile-local
+// coordinates back to lon/lat. Invalid keys and extents below 1 return nil.
+func (key QuadKey) ProjectFromTile(g orb.Geometry, extent int) orb.Geometry {
+	x, y, z := key.tileXY()
+	if g == nil || z < 0 || extent < 1 {
+		return nil
+	}
+	n := math.Exp2(float64(z))
+	e := float64(extent)
+	return project.Geometry(orb.Clone(g), func(p orb.Point) orb.Point {
+		return orb.Point{
+			tileLon(float64(x)+p[0]/e, n),
+			tileLat(float64(y)+p[1]/e, n),
+		}
+	})
+}
+
 func (key *QuadKey) ToFeature() *geojson.Feature {
 	feature := geojson.NewFeature(key.ToPolygon())
 	feature.ID = key.String()
 	return feature
 }
 
+// FeatureID selects the ID given to features built with FeatureOptions.
+type FeatureID int
+
+const (
+	// FeatureIDQuadKey sets the ID to the key string, as ToFeature does.
+	FeatureIDQuadKey FeatureID = iota
+	// FeatureIDQuadInt sets the ID to the key's QuadInt, for consumers such as
+	// vector tile encoders that need numeric IDs. Keys deeper than
+	// MaxQuadIntZoom get no ID.
+	FeatureIDQuadInt
+	// FeatureIDNone leaves the ID unset.
+	FeatureIDNone
+)
+
+// FeatureOptions tunes the features built by ToFeatureWithOptions and
+// ToFeatureCollectionWithOptions. The zero value builds the same features as
+// ToFeature.
+type FeatureOptions struct {
+	// ID selects the feature ID.
+	ID FeatureID
+	// KeyProperty, if set, is the name of a property holding the key string,
+	// such as "quadkey", which KeysFromFeatureCollection falls back to.
+	KeyProperty string
+	// XYZ adds the tile coordinates as "z", "x" and "y" properties.
+	XYZ bool
+	// Properties, if set, is called once per key and its properties are
+	// added last, overriding any set above.
+	Properties func(key QuadKey) geojson.Properties
+}
+
+// ToFeatureWithOptions returns the tile polygon as a feature with the ID and
+// properties opts asks for.
+func (key QuadKey) ToFeatureWithOptions(opts FeatureOptions) *geojson.Feature {
+	feature := geojson.NewFeature(key.ToPolygon())
+	switch opts.ID {
+	case FeatureIDQuadKey:
+		feature.ID = key.String()
+	case FeatureIDQuadInt:
+		if q, err := key.QuadInt(); err == nil {
+			feature.ID = uint64(q)
+		}
+	}
+	if opts.KeyProperty != "" {
+		feature.Properties[opts.KeyProperty] = key.String()
+	}
+	if opts.XYZ {
+		x, y, z := key.XYZ()
+		feature.Properties["z"] = z
+		feature.Properties["x"] = x
+		feature.Properties["y"] = y
+	}
+	if opts.Properties != nil {
+		for name, value := range opts.Properties(key) {
+			feature.Properties[name] = value
+		}
+	}
+	return feature
+}
+
+// --------------------------
+// struct Relationship
+// --------------------------
+
+// Relationship is the spatial relation between two keys, as returned by
+// QuadKey.Relationship.
+type Relationship int
+
+const (
+	// Disjoint keys do not overlap (or at least one is invalid).
+	Disjoint Relationship = iota
+	// Equal keys are the same tile.
+	Equal
+	// Ancestor means the receiver contains the other key.
+	Ancestor
+	// Descendant means the receiver lies inside the other key.
+	Descendant
+)
+
+func (rel Relationship) String() string {
+	switch rel {
+	case Disjoint:
+		return "disjoint"
+	case Equal:
+		return "equal"
+	case Ancestor:
+		return "ancestor"
+	case Descendant:
+		return "descendant"
+	default:
+		return fmt.Sprintf("Relationship(%d)", int(rel))
+	}
+}
+
 // --------------------------
 // internal function's
 // --------------------------
@@ -173,62 +580,125 @@ func normalize(lon, lat float64) (float64, float64) {
 	return lon, lat
 }
 
+// tileXY decodes the key like XYZ, but into uint64 coordinates, which hold
+// every zoom up to MaxZoom on any platform. z is -1 for an invalid key.
+func (key QuadKey) tileXY() (x, y uint64, z int) {
+	if key == "" || len(key) > MaxZoom {
+		return 0, 0, -1
+	}
+	for i := 0; i < len(key); i++ {
+		d := key[i] - '0'
+		if d > 3 {
+			return 0, 0, -1
+		}
+		x = x<<1 | uint64(d&1)
+		y = y<<1 | uint64(d>>1)
+	}
+	return x, y, len(key)
+}
+
+// tileKey is FromXYZ for uint64 coordinates.
+func tileKey(x, y uint64, z int) QuadKey {
+	return QuadKey(appendTileKey(make([]byte, 0, max(z, 0)), x, y, z))
+}
+
+// appendTileKey appends the z digits of tile (x, y), the low z bits of each,
+// to dst. Zooms outside [1, MaxZoom] append nothing.
+func appendTileKey(dst []byte, x, y uint64, z int) []byte {
+	if z > MaxZoom {
+		return dst
+	}
+	for i := z - 1; i >= 0; i-- {
+		dst = append(dst, '0'+byte(x>>i&1)+byte(y>>i&1)<<1)
+	}
+	return dst
+}
+
+// tileBound returns the lon/lat bound of tile (x, y) at zoom z. Each edge is
+// computed from its grid line alone, as the exact fraction x/2^z or y/2^z,
+// so tiles sharing an edge, at the same or different zooms, agree on it bit
+// for bit.
+func tileBound(x, y uint64, z int) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{edgeLon(x, z), edgeLat(y+1, z)},
+		Max: orb.Point{edgeLon(x+1, z), edgeLat(y, z)},
+	}
+}
+
+// edgeLon returns the longitude of grid column line x at zoom z.
+func edgeLon(x uint64, z int) float64 {
+	return math.Ldexp(float64(x), -z)*360 - 180
+}
+
+// edgeLat returns the latitude of grid row line y at zoom z.
+func edgeLat(y uint64, z int) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-math.Ldexp(float64(y), 1-z)))) * 180 / math.Pi
+}
+
+// tileLon converts a (possibly fractional) tile column on a grid of n columns
+// to longitude.
+func tileLon(x, n float64) float64 {
+	return x/n*360 - 180
+}
+
+// tileLat converts a (possibly fractional) tile row on a grid of n rows to
+// latitude on the Web Mercator projection.
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// tileIndex returns the tile holding fractional position f on a grid of n
+// tiles, clamped to the grid.
+func tileIndex(f, n float64) uint64 {
+	return uint64(math.Max(0, math.Min(math.Floor(f), n-1)))
+}
+
 func toX(lon float64, z int) int {
 	n := math.Exp2(float64(z))
-	x := math.Floor((lon + 180) / 360 * n)
+	x := math.Floor(fracX(lon, n))
 	return int(math.Max(0, math.Min(x, n-1)))
 }
 
 func toY(lat float64, z int) int {
 	n := math.Exp2(float64(z))
-	rad := lat * math.Pi / 180
-	y := math.Floor((1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * n)
+	y := math.Floor(fracY(lat, n))
 	return int(math.Max(0, math.Min(y, n-1)))
 }
 
-// --------------------------
-// global function's
-// --------------------------
-
-func FromXYZ(x, y, z int) QuadKey {
-	quadkey := make([]byte, z)
-	for i := z; i > 0; i-- {
-		digit := byte('0')
-		mask := 1 << (i - 1)
-		if (x & mask) != 0 {
-			digit++
-		}
-		if (y & mask) != 0 {
-			digit += 2
-		}
-		quadkey[z-i] = digit
-	}
-	return QuadKey(quadkey)
+// fracX converts longitude to a fractional tile column on a grid of n columns.
+func fracX(lon, n float64) float64 {
+	return (lon + 180) / 360 * n
 }
 
-func FromLonLat(lon, lat float64, zoom int) QuadKey {
-	lon, lat = normalize(lon, lat)
-	x := toX(lon, zoom)
-	y := toY(lat, zoom)
-	return FromXYZ(x, y, zoom)
-}
-
-func FromPoint(point orb.Point, zoom int) QuadKey {
-	lon, lat := normalize(point.Lon(), point.Lat())
-	x := toX(lon, zoom)
-	y := toY(lat, zoom)
-	return FromXYZ(x, y, zoom)
+// fracY converts latitude to a fractional tile row on a grid of n rows.
+func fracY(lat, n float64) float64 {
+	rad := lat * math.Pi / 180
+	return (1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * n
 }
 
-func FromKey(key string) (QuadKey, error) {
-	quadkey := QuadKey(key)
-	if err := quadkey.Valid(); err != nil {
-		return "", err
+// tileRange returns the inclusive range of tile indices KeysInBound covers for
+// bound at zoom, as tileSpan computes it. Empty spans give maxX < minX.
+func tileRange(bound orb.Bound, zoom int) (minX, minY, maxX, maxY int) {
+	x0, y0, x1, y1, ok := tileSpan(bound, zoom)
+	if !ok {
+		return 0, 0, -1, -1
 	}
-	return quadkey, nil
+	return int(x0), int(y0), int(x1), int(y1)
 }
 
-func KeysInBound(bound orb.Bound, zoom int) []QuadKey {
+// tileSpan returns the inclusive range of tile indices covering bound at
+// zoom, in uint64 so zoom 32 indices fit on 32-bit platforms. It reports
+// false for zooms outside [1, MaxZoom] and bounds with a NaN or infinite
+// coordinate.
+func tileSpan(bound orb.Bound, zoom int) (minX, minY, maxX, maxY uint64, ok bool) {
+	if zoom < 1 || zoom > MaxZoom {
+		return 0, 0, 0, 0, false
+	}
+	for _, v := range []float64{bound.Min[0], bound.Min[1], bound.Max[0], bound.Max[1]} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, 0, 0, 0, false
+		}
+	}
 	west, south := normalize(bound.Left(), bound.Bottom())
 	east, north := normalize(bound.Right(), bound.Top())
 
@@ -249,10 +719,11 @@ func KeysInBound(bound orb.Bound, zoom int) []QuadKey {
 		southIn = math.Nextafter(southIn, north)
 	}
 
-	minX := toX(west, zoom)
-	maxX := toX(eastIn, zoom)
-	minY := toY(north, zoom)
-	maxY := toY(southIn, zoom)
+	n := math.Exp2(float64(zoom))
+	minX = tileIndex(fracX(west, n), n)
+	maxX = tileIndex(fracX(eastIn, n), n)
+	minY = tileIndex(fracY(north, n), n)
+	maxY = tileIndex(fracY(southIn, n), n)
 
 	// If the bound is inverted or crosses the dateline, normalization can produce min>max.
 	// We keep the current behavior by swapping, but callers that require dateline-aware
@@ -263,21 +734,295 @@ func KeysInBound(bound orb.Bound, zoom int) []QuadKey {
 	if minY > maxY {
 		minY, maxY = maxY, minY
 	}
+	return minX, minY, maxX, maxY, true
+}
+
+// --------------------------
+// global function's
+// --------------------------
+
+func FromXYZ(x, y, z int) QuadKey {
+	return tileKey(uint64(x), uint64(y), z)
+}
+
+// FromXYZChecked is FromXYZ for untrusted input: it returns an error instead
+// of a meaningless key if z is out of range or the tile lies outside the
+// grid.
+func FromXYZChecked(x, y, z int) (QuadKey, error) {
+	if z < 1 || z > MaxZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxZoom)
+	}
+	if x < 0 || y < 0 || int64(x) >= 1<<z || int64(y) >= 1<<z {
+		return "", fmt.Errorf("tile (%d, %d) is outside zoom %d", x, y, z)
+	}
+	return FromXYZ(x, y, z), nil
+}
+
+// FromXYZAppend appends the digits of FromXYZ(x, y, z) to dst and returns
+// the extended buffer, so hot paths can build keys into a reused buffer
+// without allocating a string per tile.
+func FromXYZAppend(dst []byte, x, y, z int) []byte {
+	return appendTileKey(dst, uint64(x), uint64(y), z)
+}
+
+func FromLonLat(lon, lat float64, zoom int) QuadKey {
+	lon, lat = normalize(lon, lat)
+	n := math.Exp2(float64(zoom))
+	return tileKey(tileIndex(fracX(lon, n), n), tileIndex(fracY(lat, n), n), zoom)
+}
+
+func FromPoint(point orb.Point, zoom int) QuadKey {
+	return FromLonLat(point.Lon(), point.Lat(), zoom)
+}
+
+// FromPoints returns the key of every point at zoom, as FromPoint does, in
+// one pass tuned for large batches; see AppendFromPoints.
+func FromPoints(pts []orb.Point, zoom int) []QuadKey {
+	return AppendFromPoints(make([]QuadKey, 0, len(pts)), pts, zoom)
+}
+
+// AppendFromPoints appends the key of every point at zoom to dst and returns
+// the extended slice, so a destination can be reused across batches. Keys
+// are identical to FromPoint's, but the per-zoom constants are computed
+// once, points already in range skip normalization, and the digits of the
+// whole batch share one allocation, which stays alive as long as any of the
+// keys does. Zooms outside [1, MaxZoom] give empty keys.
+func AppendFromPoints(dst []QuadKey, pts []orb.Point, zoom int) []QuadKey {
+	if zoom < 1 || zoom > MaxZoom {
+		for range pts {
+			dst = append(dst, "")
+		}
+		return dst
+	}
+
+	n := math.Exp2(float64(zoom))
+	buf := make([]byte, len(pts)*zoom)
+	for i, p := range pts {
+		lon, lat := p.Lon(), p.Lat()
+		if !(lon > -180 && lon <= 180) || lat > MERCATOR_MAX_LAT || lat < -MERCATOR_MAX_LAT {
+			lon, lat = normalize(lon, lat)
+		}
+		x, y := tileIndex(fracX(lon, n), n), tileIndex(fracY(lat, n), n)
+
+		digits := buf[i*zoom : (i+1)*zoom]
+		for j := range digits {
+			shift := zoom - 1 - j
+			digits[j] = '0' + byte(x>>shift&1) + byte(y>>shift&1)<<1
+		}
+	}
+
+	keys := string(buf)
+	for i := range pts {
+		dst = append(dst, QuadKey(keys[i*zoom:(i+1)*zoom]))
+	}
+	return dst
+}
+
+func FromKey(key string) (QuadKey, error) {
+	quadkey := QuadKey(key)
+	if err := quadkey.Valid(); err != nil {
+		return "", err
+	}
+	return quadkey, nil
+}
+
+// MustFromKey is FromKey for keys known to be valid, such as constants in
+// configuration and test code. It panics if the key is invalid.
+func MustFromKey(key string) QuadKey {
+	quadkey, err := FromKey(key)
+	if err != nil {
+		panic("quadkey: " + err.Error())
+	}
+	return quadkey
+}
+
+// CommonAncestor returns the deepest key containing all of the given keys,
+// i.e. their longest common prefix. A single key is its own common ancestor.
+// An error is returned when no keys are given, a key is invalid, or the keys
+// only share the whole world (no common zoom 1 tile).
+func CommonAncestor(keys ...QuadKey) (QuadKey, error) {
+	if len(keys) == 0 {
+		return "", errors.New("no keys given")
+	}
+
+	prefix := keys[0]
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return "", err
+		}
+		n := min(len(prefix), len(key))
+		i := 0
+		for i < n && prefix[i] == key[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+
+	if prefix == "" {
+		return "", errors.New("keys have no common ancestor")
+	}
+	return prefix, nil
+}
+
+// enclosingTolerance, in tiles at MaxZoom, absorbs the rounding of
+// tile edges converted to lon/lat and back.
+const enclosingTolerance = 1e-5
+
+// EnclosingKey returns the deepest tile fully containing the bound. Edges
+// within rounding error of a tile border count as inside it, so the bound of
+// a tile returns that tile. An error is returned for an inverted bound, or
+// when only the whole world encloses it.
+func EnclosingKey(bound orb.Bound) (QuadKey, error) {
+	if bound.Min.Lon() > bound.Max.Lon() || bound.Min.Lat() > bound.Max.Lat() {
+		return "", errors.New("bound is empty")
+	}
+
+	n := math.Exp2(MaxZoom)
+	west, south := normalize(bound.Left(), bound.Bottom())
+	east, north := normalize(bound.Right(), bound.Top())
+	minX, maxX := enclosingSpan(fracX(west, n), fracX(east, n), n)
+	minY, maxY := enclosingSpan(fracY(north, n), fracY(south, n), n)
+	return enclosingKey(tileKey(minX, minY, MaxZoom), tileKey(maxX, maxY, MaxZoom))
+}
+
+// enclosingSpan returns the tiles holding the fractional span [lo, hi],
+// shrunk by enclosingTolerance on both sides.
+func enclosingSpan(lo, hi, n float64) (first, last uint64) {
+	lo, hi = lo+enclosingTolerance, hi-enclosingTolerance
+	if hi < lo {
+		lo = (lo + hi) / 2
+		hi = lo
+	}
+	return tileIndex(lo, n), tileIndex(hi, n)
+}
+
+// EnclosingKeyForGeometry returns the deepest tile containing every point of
+// the geometry, as FromPoint places them. An error is returned for a nil or
+// empty geometry, or when only the whole world encloses it.
+func EnclosingKeyForGeometry(g orb.Geometry) (QuadKey, error) {
+	if g == nil {
+		return "", errors.New("geometry is nil")
+	}
+	b := g.Bound()
+	if b.Min.Lon() > b.Max.Lon() || b.Min.Lat() > b.Max.Lat() {
+		return "", errors.New("geometry is empty")
+	}
+	return enclosingKey(FromPoint(b.LeftTop(), MaxZoom), FromPoint(b.RightBottom(), MaxZoom))
+}
+
+// enclosingKey returns the common ancestor of the north-west and south-east
+// tiles of a range.
+func enclosingKey(nw, se QuadKey) (QuadKey, error) {
+	key, err := CommonAncestor(nw, se)
+	if err != nil {
+		return "", errors.New("only the world tile encloses the input")
+	}
+	return key, nil
+}
+
+func KeysInBound(bound orb.Bound, zoom int) []QuadKey {
+	minX, minY, maxX, maxY := tileRange(bound, zoom)
 
 	// Half-open bounds can legitimately produce an empty set (e.g. zero width/height).
 	if maxX < minX || maxY < minY {
 		return []QuadKey{}
 	}
 
-	keys := make([]QuadKey, 0, (maxX-minX+1)*(maxY-minY+1))
-	for x := minX; x <= maxX; x++ {
-		for y := minY; y <= maxY; y++ {
-			keys = append(keys, FromXYZ(x, y, zoom))
-		}
+	keys := make([]QuadKey, 0, min(CountKeysInBound(bound, zoom), 1<<16))
+	for key := range IterKeysInBound(bound, zoom) {
+		keys = append(keys, key)
 	}
 	return keys
 }
 
+// CountKeysInBound returns the number of keys KeysInBound would return,
+// computed from the tile range without generating them. Counts too large for
+// an int64, such as the whole world at zoom 32, saturate at math.MaxInt64.
+func CountKeysInBound(bound orb.Bound, zoom int) int64 {
+	minX, minY, maxX, maxY, ok := tileSpan(bound, zoom)
+	if !ok {
+		return 0
+	}
+	w, h := maxX-minX+1, maxY-minY+1
+	if w > math.MaxInt64/h {
+		return math.MaxInt64
+	}
+	return int64(w * h)
+}
+
+// BestZoom returns the deepest zoom, up to MaxZoom, at which KeysInBound returns
+// at most maxTiles keys for the bound. It returns 0 when even zoom 1 exceeds
+// the budget. Counts come from CountKeysInBound, so nothing is generated.
+func BestZoom(bound orb.Bound, maxTiles int) int {
+	best := 0
+	for z := 1; z <= MaxZoom; z++ {
+		// Counts never shrink with zoom, so the first miss ends the search.
+		if CountKeysInBound(bound, z) > int64(maxTiles) {
+			break
+		}
+		best = z
+	}
+	return best
+}
+
+// KeysInBoundLimit is KeysInBound with a cap on the result size. If the bound
+// covers more than maxKeys tiles at zoom, it returns a *TooManyTilesError
+// (matching ErrTooManyTiles) carrying the would-be count, before allocating
+// anything.
+func KeysInBoundLimit(bound orb.Bound, zoom int, maxKeys int) ([]QuadKey, error) {
+	if count := CountKeysInBound(bound, zoom); count > int64(maxKeys) {
+		return nil, &TooManyTilesError{Count: count, Limit: maxKeys}
+	}
+	return KeysInBound(bound, zoom), nil
+}
+
+// IterKeysInBound yields the same keys as KeysInBound, in the same order
+// (column by column from the west, north to south within a column), without
+// materializing them.
+func IterKeysInBound(bound orb.Bound, zoom int) iter.Seq[QuadKey] {
+	return func(yield func(QuadKey) bool) {
+		minX, minY, maxX, maxY := tileRange(bound, zoom)
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				if !yield(FromXYZ(x, y, zoom)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// KeysInBoundCtx is KeysInBound that stops early when ctx is done, returning
+// nil and ctx.Err().
+func KeysInBoundCtx(ctx context.Context, bound orb.Bound, zoom int) ([]QuadKey, error) {
+	return KeysInBoundProgress(ctx, bound, zoom, nil)
+}
+
+// KeysInBoundProgress is KeysInBoundCtx that also reports its progress to fn,
+// if non-nil. The estimate is exact, from CountKeysInBound.
+func KeysInBoundProgress(ctx context.Context, bound orb.Bound, zoom int, fn ProgressFunc) ([]QuadKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	count := CountKeysInBound(bound, zoom)
+	prog := newProgress(fn, count)
+	keys := make([]QuadKey, 0, min(count, 1<<16))
+	for key := range IterKeysInBound(bound, zoom) {
+		// Checking every key would dominate the loop; every 1024 keeps
+		// cancellation prompt.
+		if len(keys)%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+		prog.add()
+	}
+	prog.finish()
+	return keys, nil
+}
+
 func ToFeatureCollection(keys ...QuadKey) *geojson.FeatureCollection {
 	collection := geojson.NewFeatureCollection()
 	for _, key := range keys {
@@ -285,3 +1030,65 @@ func ToFeatureCollection(keys ...QuadKey) *geojson.FeatureCollection {
 	}
 	return collection
 }
+
+// ToFeatureCollectionWithOptions is ToFeatureCollection with each feature
+// built as opts asks.
+func ToFeatureCollectionWithOptions(opts FeatureOptions, keys ...QuadKey) *geojson.FeatureCollection {
+	collection := geojson.NewFeatureCollection()
+	for _, key := range keys {
+		collection.Append(key.ToFeatureWithOptions(opts))
+	}
+	return collection
+}
+
+// ToLabelFeatureCollection returns one Point feature per key at the tile
+// center, with the key string as ID and "quadkey", "z", "x" and "y"
+// properties, for labeling tiles with a symbol layer. Invalid keys are
+// skipped.
+func ToLabelFeatureCollection(keys ...QuadKey) *geojson.FeatureCollection {
+	collection := geojson.NewFeatureCollection()
+	for _, key := range keys {
+		if key.Valid() != nil {
+			continue
+		}
+		x, y, z := key.XYZ()
+		feature := geojson.NewFeature(key.Center())
+		feature.ID = key.String()
+		feature.Properties["quadkey"] = key.String()
+		feature.Properties["z"] = z
+		feature.Properties["x"] = x
+		feature.Properties["y"] = y
+		collection.Append(feature)
+	}
+	return collection
+}
+
+// KeysFromFeatureCollection reads back the keys of a collection, such as one
+// built by ToFeatureCollection, in feature order. Each feature's key is its
+// string ID or, failing that, its "quadkey" property. An error is returned
+// for a feature with neither, or with an invalid key.
+func KeysFromFeatureCollection(fc *geojson.FeatureCollection) ([]QuadKey, error) {
+	if fc == nil {
+		return []QuadKey{}, nil
+	}
+
+	keys := make([]QuadKey, 0, len(fc.Features))
+	for i, feature := range fc.Features {
+		if feature == nil {
+			return nil, fmt.Errorf("feature %d is nil", i)
+		}
+		id, ok := feature.ID.(string)
+		if !ok {
+			id, ok = feature.Properties["quadkey"].(string)
+		}
+		if !ok {
+			return nil, fmt.Errorf("feature %d has no quadkey id or property", i)
+		}
+		key, err := FromKey(id)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -1,12 +1,20 @@
 package quadkey
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"maps"
 	"math"
+	"math/rand"
+	"slices"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
 )
 
 // helper to compare ints
@@ -43,6 +51,39 @@ func TestQuadKeyValid(t *testing.T) {
 	}
 }
 
+func TestValidErrors(t *testing.T) {
+	if err := QuadKey("").Valid(); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("empty key: got %v, want ErrEmptyKey", err)
+	}
+
+	err := QuadKey("01a3").Valid()
+	var digitErr *InvalidDigitError
+	if !errors.Is(err, ErrInvalidDigit) || !errors.As(err, &digitErr) {
+		t.Fatalf("got %v, want an *InvalidDigitError", err)
+	}
+	if digitErr.Index != 2 || digitErr.Digit != 'a' {
+		t.Fatalf("got index %d, digit %q", digitErr.Index, digitErr.Digit)
+	}
+	if want := `key contains invalid digit at index 2: 'a'`; err.Error() != want {
+		t.Fatalf("got %q, want %q", err, want)
+	}
+	if _, err := FromKey("01-3"); !errors.Is(err, ErrInvalidDigit) {
+		t.Fatalf("FromKey: got %v, want ErrInvalidDigit", err)
+	}
+}
+
+func TestMustFromKey(t *testing.T) {
+	if got := MustFromKey("0231"); got != "0231" {
+		t.Fatalf("got %q", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for an invalid key")
+		}
+	}()
+	MustFromKey("01a3")
+}
+
 func TestFromXYZAndXYZRoundTrip(t *testing.T) {
 	// test a handful of x/y points across multiple zoom levels
 	type tc struct {
@@ -56,6 +97,8 @@ func TestFromXYZAndXYZRoundTrip(t *testing.T) {
 		{3, 0, 2},
 		{10, 12, 5},
 		{123, 456, 10},
+		{1<<30 - 1, 0, 30},
+		{987654321, 123456789, 30},
 	}
 
 	for _, c := range cases {
@@ -71,9 +114,139 @@ func TestFromXYZAndXYZRoundTrip(t *testing.T) {
 }
 
 func TestXYZInvalidKey(t *testing.T) {
-	x, y, z := QuadKey("01a3").XYZ()
-	if x != -1 || y != -1 || z != -1 {
-		t.Fatalf("expected (-1,-1,-1) for invalid key, got (%d,%d,%d)", x, y, z)
+	for _, key := range []QuadKey{"", "01a3", "4", "012/", "0 1"} {
+		x, y, z := key.XYZ()
+		if x != -1 || y != -1 || z != -1 {
+			t.Fatalf("%q: expected (-1,-1,-1) for invalid key, got (%d,%d,%d)", key, x, y, z)
+		}
+	}
+}
+
+func TestMaxZoom(t *testing.T) {
+	deepest := QuadKey(strings.Repeat("3", MaxZoom))
+	if err := deepest.Valid(); err != nil {
+		t.Fatalf("zoom %d key: unexpected error: %v", MaxZoom, err)
+	}
+	if err := (deepest + "0").Valid(); err == nil {
+		t.Fatalf("expected an error for a key deeper than MaxZoom")
+	}
+
+	// Coordinates at MaxZoom use all 32 bits.
+	if x, y, z := deepest.tileXY(); x != 1<<MaxZoom-1 || y != 1<<MaxZoom-1 || z != MaxZoom {
+		t.Fatalf("tileXY: got (%d,%d,%d)", x, y, z)
+	}
+	if b := deepest.Bound(); !b.Contains(deepest.Center()) || b.Max[0] != 180 {
+		t.Fatalf("Bound %+v does not hold Center %v", b, deepest.Center())
+	}
+	if got := FromXYZ(deepest.XYZ()); got != deepest {
+		t.Fatalf("FromXYZ: got %q", got)
+	}
+	if got := FromLonLat(179.9999999, -85, MaxZoom); got.Z() != MaxZoom || got.Valid() != nil {
+		t.Fatalf("FromLonLat: got %q", got)
+	}
+	if got := FromMorton(deepest.Morton(), MaxZoom); got != deepest {
+		t.Fatalf("Morton round trip: got %q", got)
+	}
+
+	for _, got := range []QuadKey{FromXYZ(0, 0, MaxZoom+1), FromLonLat(1, 1, MaxZoom+1), FromPoints([]orb.Point{{1, 1}}, MaxZoom+1)[0]} {
+		if got != "" {
+			t.Fatalf("zoom %d: got %q, want an empty key", MaxZoom+1, got)
+		}
+	}
+	if x, y, z := (deepest + "0").XYZ(); x != -1 || y != -1 || z != -1 {
+		t.Fatalf("XYZ of a too deep key: got (%d,%d,%d)", x, y, z)
+	}
+}
+
+func TestZoomsBeyondMaxZoom(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{139.7, 35.6}, Max: orb.Point{139.7001, 35.6001}}
+	poly := b.ToPolygon()
+	for _, zoom := range []int{MaxZoom + 1, MaxZoom + 2, 0, -1} {
+		if got := Cover(poly, zoom); len(got) != 0 {
+			t.Fatalf("Cover at zoom %d: got %d keys", zoom, len(got))
+		}
+		if got := KeysInBound(b, zoom); len(got) != 0 {
+			t.Fatalf("KeysInBound at zoom %d: got %d keys", zoom, len(got))
+		}
+		if got := CountKeysInBound(b, zoom); got != 0 {
+			t.Fatalf("CountKeysInBound at zoom %d: got %d", zoom, got)
+		}
+		if got := slices.Collect(IterKeysInBound(b, zoom)); len(got) != 0 {
+			t.Fatalf("IterKeysInBound at zoom %d: got %d keys", zoom, len(got))
+		}
+		if got := KeysAlongLine(orb.LineString{b.Min, b.Max}, zoom); len(got) != 0 {
+			t.Fatalf("KeysAlongLine at zoom %d: got %d keys", zoom, len(got))
+		}
+		if got := (TileScheme{}).ViewportKeys(b.Center(), zoom, 512, 512); len(got) != 0 {
+			t.Fatalf("ViewportKeys at zoom %d: got %d keys", zoom, len(got))
+		}
+	}
+
+	deepest := QuadKey(strings.Repeat("3", MaxZoom))
+	if got := deepest.Children(); got != nil {
+		t.Fatalf("Children at MaxZoom: got %q, want nil", got)
+	}
+	if _, err := deepest.ChildrenChecked(); err == nil {
+		t.Fatalf("ChildrenChecked at MaxZoom: expected an error")
+	}
+	if got := slices.Collect(deepest[:MaxZoom-1].DescendantsAtZoom(MaxZoom + 1)); len(got) != 0 {
+		t.Fatalf("DescendantsAtZoom(%d): got %d keys", MaxZoom+1, len(got))
+	}
+	if got := slices.Collect(deepest[:MaxZoom-1].DescendantsAtZoom(MaxZoom)); len(got) != 4 {
+		t.Fatalf("DescendantsAtZoom(%d): got %d keys, want 4", MaxZoom, len(got))
+	}
+
+	levels := map[int]int{}
+	for z, keys := range IterCoverPyramid(poly, MaxZoom-1, MaxZoom+2) {
+		levels[z] = len(keys)
+		for _, k := range keys {
+			if k.Valid() != nil {
+				t.Fatalf("IterCoverPyramid zoom %d: invalid key %q", z, k)
+			}
+		}
+	}
+	if len(levels) != 2 || levels[MaxZoom] == 0 {
+		t.Fatalf("IterCoverPyramid: got levels %v, want zooms %d and %d", levels, MaxZoom-1, MaxZoom)
+	}
+
+	// A tiny bound keeps the zoom 32 covering small.
+	tiny := orb.Bound{Min: orb.Point{139.7, 35.6}, Max: orb.Point{139.7000001, 35.6000001}}.ToPolygon()
+	for _, k := range (RegionCoverer{MinZoom: MaxZoom + 1, MaxZoom: MaxZoom + 2}).Covering(tiny) {
+		if k.Z() != MaxZoom {
+			t.Fatalf("RegionCoverer beyond MaxZoom: got key %q at zoom %d", k, k.Z())
+		}
+	}
+}
+
+func TestCheckedVariants(t *testing.T) {
+	x, y, z, err := QuadKey("0231").XYZChecked()
+	if err != nil || x != 3 || y != 6 || z != 4 {
+		t.Fatalf("XYZChecked: got (%d,%d,%d), %v", x, y, z, err)
+	}
+	if b, err := QuadKey("0231").BoundChecked(); err != nil || b != QuadKey("0231").Bound() {
+		t.Fatalf("BoundChecked: got %+v, %v", b, err)
+	}
+	if c, err := QuadKey("0231").ChildrenChecked(); err != nil || !slices.Equal(c, QuadKey("0231").Children()) {
+		t.Fatalf("ChildrenChecked: got %v, %v", c, err)
+	}
+
+	for _, key := range []QuadKey{"", "01a3"} {
+		if x, y, z, err := key.XYZChecked(); err == nil || x != -1 || y != -1 || z != -1 {
+			t.Fatalf("XYZChecked(%q): got (%d,%d,%d), %v", key, x, y, z, err)
+		}
+		if b, err := key.BoundChecked(); err == nil || b != (orb.Bound{}) {
+			t.Fatalf("BoundChecked(%q): got %+v, %v", key, b, err)
+		}
+		if c, err := key.ChildrenChecked(); err == nil || c != nil {
+			t.Fatalf("ChildrenChecked(%q): got %v, %v", key, c, err)
+		}
+	}
+}
+
+func BenchmarkXYZ(b *testing.B) {
+	key := FromXYZ(987654321, 123456789, 30)
+	for b.Loop() {
+		key.XYZ()
 	}
 }
 
@@ -97,6 +270,41 @@ func TestParent(t *testing.T) {
 	}
 }
 
+func TestAncestorAtZoom(t *testing.T) {
+	key := QuadKey("13300221")
+	tests := []struct {
+		z       int
+		want    QuadKey
+		wantErr bool
+	}{
+		{1, "1", false},
+		{4, "1330", false},
+		{8, "13300221", false},
+		{0, "", true},
+		{9, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := key.AncestorAtZoom(tt.z)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("zoom %d: expected error, got %q", tt.z, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("zoom %d: unexpected error: %v", tt.z, err)
+		}
+		if got != tt.want {
+			t.Fatalf("zoom %d: got %q, want %q", tt.z, got, tt.want)
+		}
+	}
+
+	if _, err := QuadKey("01a3").AncestorAtZoom(1); err == nil {
+		t.Fatalf("expected error for invalid key")
+	}
+}
+
 func TestChildren(t *testing.T) {
 	// children should be 4 tiles at the next zoom level
 	k := QuadKey("12")
@@ -120,6 +328,104 @@ func TestChildren(t *testing.T) {
 	}
 }
 
+func TestDescendantsAtZoom(t *testing.T) {
+	key := QuadKey("12")
+
+	got := []QuadKey{}
+	for k := range key.DescendantsAtZoom(4) {
+		got = append(got, k)
+	}
+	assertEqualInt(t, "descendant count", len(got), 16)
+	if got[0] != "1200" || got[15] != "1233" {
+		t.Fatalf("descendant order: first=%q last=%q", got[0], got[15])
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1] >= got[i] {
+			t.Fatalf("descendants not sorted at %d: %q >= %q", i, got[i-1], got[i])
+		}
+	}
+
+	self := []QuadKey{}
+	for k := range key.DescendantsAtZoom(2) {
+		self = append(self, k)
+	}
+	if len(self) != 1 || self[0] != key {
+		t.Fatalf("descendants at own zoom: got %v", self)
+	}
+
+	for k := range key.DescendantsAtZoom(1) {
+		t.Fatalf("expected no descendants at coarser zoom, got %q", k)
+	}
+	for k := range QuadKey("01a3").DescendantsAtZoom(5) {
+		t.Fatalf("expected no descendants for invalid key, got %q", k)
+	}
+}
+
+func TestDescendantsAtZoomStopsEarly(t *testing.T) {
+	n := 0
+	for range QuadKey("0").DescendantsAtZoom(20) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assertEqualInt(t, "yielded", n, 3)
+}
+
+func TestSiblings(t *testing.T) {
+	got := QuadKey("0132").Siblings()
+	want := []QuadKey{"0130", "0131", "0133"}
+	if len(got) != len(want) {
+		t.Fatalf("siblings length: got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("siblings[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	root := QuadKey("2").Siblings()
+	if len(root) != 3 || root[0] != "0" || root[1] != "1" || root[2] != "3" {
+		t.Fatalf("root siblings: got %v", root)
+	}
+
+	if got := QuadKey("01a3").Siblings(); len(got) != 0 {
+		t.Fatalf("expected no siblings for invalid key, got %v", got)
+	}
+}
+
+func TestRelationship(t *testing.T) {
+	tests := []struct {
+		a, b QuadKey
+		want Relationship
+	}{
+		{"0123", "0123", Equal},
+		{"01", "0123", Ancestor},
+		{"0123", "01", Descendant},
+		{"0123", "0132", Disjoint},
+		// Sharing a parent is not containment.
+		{"012", "0130", Disjoint},
+		{"01a3", "01", Disjoint},
+		{"01", "", Disjoint},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Relationship(tt.b); got != tt.want {
+			t.Fatalf("%q.Relationship(%q): got %s, want %s", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if !QuadKey("01").IsAncestorOf("0123") || QuadKey("0123").IsAncestorOf("01") {
+		t.Fatalf("IsAncestorOf mismatch")
+	}
+	if !QuadKey("0123").IsDescendantOf("01") || QuadKey("01").IsDescendantOf("0123") {
+		t.Fatalf("IsDescendantOf mismatch")
+	}
+	if QuadKey("01").IsAncestorOf("01") || QuadKey("01").IsDescendantOf("01") {
+		t.Fatalf("a key is neither its own ancestor nor descendant")
+	}
+}
+
 func TestBoundIsNonEmptyForValidKey(t *testing.T) {
 	b := QuadKey("0").Bound()
 	if b == (orb.Bound{}) {
@@ -147,6 +453,202 @@ func TestBoundInvalidKeyReturnsEmpty(t *testing.T) {
 	}
 }
 
+func TestBoundSharedEdges(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for range 500 {
+		z := 1 + rng.Intn(MaxZoom)
+		n := int64(1) << z
+		x, y := uint64(rng.Int63n(n-1)), uint64(rng.Int63n(n-1))
+		b := tileKey(x, y, z).Bound()
+		east, south := tileKey(x+1, y, z).Bound(), tileKey(x, y+1, z).Bound()
+		if b.Max[0] != east.Min[0] || b.Min[1] != south.Max[1] {
+			t.Fatalf("tile %d/%d/%d: edges differ from its neighbors", z, x, y)
+		}
+		if z < MaxZoom {
+			// The outer children share the parent's edges.
+			nw, se := tileKey(2*x, 2*y, z+1).Bound(), tileKey(2*x+1, 2*y+1, z+1).Bound()
+			if nw.Min[0] != b.Min[0] || nw.Max[1] != b.Max[1] || se.Max[0] != b.Max[0] || se.Min[1] != b.Min[1] {
+				t.Fatalf("tile %d/%d/%d: edges differ from its children", z, x, y)
+			}
+		}
+	}
+
+	world := QuadKey("0").Bound().Union(QuadKey("3").Bound())
+	if world.Min[0] != -180 || world.Max[0] != 180 || world.Max[1] != -world.Min[1] {
+		t.Fatalf("world bound: got %+v", world)
+	}
+}
+
+func TestCenter(t *testing.T) {
+	c := QuadKey("0").Center()
+	if math.Abs(c.Lon()+90) > 1e-9 || math.Abs(c.Lat()-66.51326044311186) > 1e-9 {
+		t.Fatalf("center of \"0\": got %v", c)
+	}
+
+	// The center must fall inside its own tile at every zoom.
+	for _, key := range []QuadKey{"13300221", "3", "0000000", "2222222"} {
+		if got := FromPoint(key.Center(), key.Z()); got != key {
+			t.Fatalf("FromPoint(%q.Center()): got %q", key, got)
+		}
+	}
+
+	// Tiles mirrored across the equator have mirrored centers.
+	north := FromXYZ(5, 1, 4).Center()
+	south := FromXYZ(5, 14, 4).Center()
+	if math.Abs(north.Lat()+south.Lat()) > 1e-9 {
+		t.Fatalf("centers not symmetric: %v vs %v", north, south)
+	}
+
+	if got := QuadKey("01a3").Center(); got != (orb.Point{}) {
+		t.Fatalf("expected zero point for invalid key, got %v", got)
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	key := QuadKey("13300221")
+	if !key.ContainsPoint(key.Center()) {
+		t.Fatalf("expected tile to contain its center")
+	}
+
+	// Shared edges belong to exactly one tile, the one FromPoint picks.
+	b := key.Bound()
+	edge := orb.Point{b.Left(), (b.Top() + b.Bottom()) / 2}
+	owners := 0
+	for _, k := range append(key.Neighbors(), key) {
+		if k.ContainsPoint(edge) {
+			owners++
+			if k != FromPoint(edge, k.Z()) {
+				t.Fatalf("%q claims edge point but FromPoint disagrees", k)
+			}
+		}
+	}
+	assertEqualInt(t, "edge owners", owners, 1)
+
+	if key.ContainsPoint(orb.Point{-120, -40}) {
+		t.Fatalf("expected distant point to be outside")
+	}
+	if QuadKey("01a3").ContainsPoint(orb.Point{0, 0}) {
+		t.Fatalf("invalid key should contain nothing")
+	}
+}
+
+func TestCorners(t *testing.T) {
+	key := QuadKey("13300221")
+	b := key.Bound()
+	c := key.Corners()
+
+	want := [4]orb.Point{
+		{b.Left(), b.Top()},
+		{b.Right(), b.Top()},
+		{b.Right(), b.Bottom()},
+		{b.Left(), b.Bottom()},
+	}
+	if c != want {
+		t.Fatalf("corners: got %v, want %v", c, want)
+	}
+
+	if got := QuadKey("01a3").Corners(); got != ([4]orb.Point{}) {
+		t.Fatalf("expected zero corners for invalid key, got %v", got)
+	}
+}
+
+func TestToRingAndEdgeLineString(t *testing.T) {
+	key := QuadKey("13300221")
+	ring := key.ToRing()
+	if len(ring) != 5 || !ring.Closed() {
+		t.Fatalf("expected closed ring of 5 points, got %v", ring)
+	}
+	if ring.Orientation() != orb.CCW {
+		t.Fatalf("expected counter-clockwise ring")
+	}
+	if !ring.Equal(key.ToPolygon()[0]) {
+		t.Fatalf("ring differs from polygon exterior: %v vs %v", ring, key.ToPolygon()[0])
+	}
+
+	line := key.EdgeLineString()
+	if !line.Equal(orb.LineString(ring)) {
+		t.Fatalf("edge line differs from ring: %v", line)
+	}
+
+	if got := QuadKey("01a3").ToRing(); len(got) != 0 {
+		t.Fatalf("expected empty ring for invalid key, got %v", got)
+	}
+	if got := QuadKey("01a3").EdgeLineString(); len(got) != 0 {
+		t.Fatalf("expected empty line for invalid key, got %v", got)
+	}
+}
+
+func TestClip(t *testing.T) {
+	key := QuadKey("1202")
+	b := key.Bound()
+	c := key.Center()
+	line := orb.LineString{{b.Min[0] - 10, c[1]}, {b.Max[0] + 10, c[1]}}
+
+	got, ok := key.Clip(line).(orb.LineString)
+	if !ok || len(got) != 2 {
+		t.Fatalf("expected a clipped line, got %v", key.Clip(line))
+	}
+	if math.Abs(got[0][0]-b.Min[0]) > 1e-9 || math.Abs(got[1][0]-b.Max[0]) > 1e-9 {
+		t.Fatalf("line %v is not clipped to %v", got, b)
+	}
+	if line[0][0] != b.Min[0]-10 {
+		t.Fatalf("input was modified: %v", line)
+	}
+
+	// 64 pixels of buffer on a 256-pixel tile is a quarter of its width.
+	buffered := key.ClipBuffered(line, 64).(orb.LineString)
+	width := b.Max[0] - b.Min[0]
+	if math.Abs(buffered[0][0]-(b.Min[0]-width/4)) > 1e-9 || math.Abs(buffered[1][0]-(b.Max[0]+width/4)) > 1e-9 {
+		t.Fatalf("line %v is not clipped to the buffered tile", buffered)
+	}
+	above := orb.Point{c[0], b.Max[1] + 0.1}
+	if key.Clip(above) != nil || key.ClipBuffered(above, 64) == nil {
+		t.Fatalf("point just north of the tile should only survive the buffered clip")
+	}
+
+	far := orb.Point{b.Max[0] + 10, c[1]}
+	if key.Clip(far) != nil || key.Clip(nil) != nil || QuadKey("01a").Clip(line) != nil {
+		t.Fatalf("expected nil geometries")
+	}
+}
+
+func TestProjectToTile(t *testing.T) {
+	key := QuadKey("13300221")
+	b := key.Bound()
+	ring := key.ToRing()
+
+	got, ok := key.ProjectToTile(ring, 4096).(orb.Ring)
+	if !ok {
+		t.Fatalf("expected a ring, got %T", key.ProjectToTile(ring, 4096))
+	}
+	want := orb.Ring{{0, 4096}, {4096, 4096}, {4096, 0}, {0, 0}, {0, 4096}}
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if ring[0] != b.Min {
+		t.Fatalf("input was modified: %v", ring)
+	}
+
+	// The center lands mid-tile and comes back within a tile unit.
+	c := key.ProjectToTile(key.Center(), 4096).(orb.Point)
+	if c != (orb.Point{2048, 2048}) {
+		t.Fatalf("center projected to %v", c)
+	}
+	back := key.ProjectFromTile(c, 4096).(orb.Point)
+	width := b.Max[0] - b.Min[0]
+	if math.Abs(back[0]-key.Center()[0]) > width/4096 || math.Abs(back[1]-key.Center()[1]) > width/4096 {
+		t.Fatalf("round trip moved the center from %v to %v", key.Center(), back)
+	}
+	corner := key.ProjectFromTile(orb.Point{0, 0}, 4096).(orb.Point)
+	if math.Abs(corner[0]-b.Min[0]) > 1e-9 || math.Abs(corner[1]-b.Max[1]) > 1e-9 {
+		t.Fatalf("corner (0, 0) maps to %v, want the north-west corner of %v", corner, b)
+	}
+
+	if key.ProjectToTile(ring, 0) != nil || key.ProjectFromTile(nil, 4096) != nil || QuadKey("01a").ProjectToTile(ring, 4096) != nil {
+		t.Fatalf("expected nil geometries")
+	}
+}
+
 func TestFromLonLatClampsLatitude(t *testing.T) {
 	// latitude above mercator max should be clamped and not produce NaN bounds
 	qk := FromLonLat(0, 90, 3)
@@ -179,6 +681,86 @@ func TestJSONRoundTrip(t *testing.T) {
 	}
 }
 
+func TestTextRoundTrip(t *testing.T) {
+	orig := QuadKey("13300221")
+	text, err := orig.MarshalText()
+	if err != nil || string(text) != "13300221" {
+		t.Fatalf("MarshalText: got %q, %v", text, err)
+	}
+	var decoded QuadKey
+	if err := decoded.UnmarshalText(text); err != nil || decoded != orig {
+		t.Fatalf("UnmarshalText: got %q, %v", decoded, err)
+	}
+
+	for _, text := range []string{"", "01a3", "0124"} {
+		key := QuadKey("0")
+		if err := key.UnmarshalText([]byte(text)); err == nil {
+			t.Fatalf("%q: expected error", text)
+		}
+		if key != "0" {
+			t.Fatalf("%q: key changed to %q on error", text, key)
+		}
+	}
+	var digitErr *InvalidDigitError
+	if err := decoded.UnmarshalText([]byte("01a3")); !errors.As(err, &digitErr) {
+		t.Fatalf("got %v, want an *InvalidDigitError", err)
+	}
+}
+
+func TestTextMapKeys(t *testing.T) {
+	counts := map[QuadKey]int{"0": 1, "1320": 2}
+	b, err := json.Marshal(counts)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(b) != `{"0":1,"1320":2}` {
+		t.Fatalf("got %s", b)
+	}
+
+	var decoded map[QuadKey]int
+	if err := json.Unmarshal(b, &decoded); err != nil || !maps.Equal(decoded, counts) {
+		t.Fatalf("got %v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"01a3":1}`), &decoded); err == nil {
+		t.Fatalf("expected error for an invalid map key")
+	}
+}
+
+func TestCommonAncestor(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []QuadKey
+		want    QuadKey
+		wantErr bool
+	}{
+		{"single", []QuadKey{"0123"}, "0123", false},
+		{"siblings", []QuadKey{"0120", "0121", "0123"}, "012", false},
+		{"mixed_zoom", []QuadKey{"0123", "01", "01332"}, "01", false},
+		{"ancestor_and_descendant", []QuadKey{"013", "0132"}, "013", false},
+		{"disjoint", []QuadKey{"0123", "1123"}, "", true},
+		{"empty", nil, "", true},
+		{"invalid", []QuadKey{"0123", "01a3"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CommonAncestor(tt.keys...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestKeysInBoundContainsExpectedKey(t *testing.T) {
 	// Pick a key, use its bound, ensure KeysInBound at same zoom includes it.
 	key := QuadKey("13300221")
@@ -201,6 +783,320 @@ func TestKeysInBoundContainsExpectedKey(t *testing.T) {
 	}
 }
 
+func TestIterKeysInBoundMatchesKeysInBound(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	want := KeysInBound(bound, 10)
+
+	got := []QuadKey{}
+	for k := range IterKeysInBound(bound, 10) {
+		got = append(got, k)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("length: got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("keys[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Order is column-major: x never decreases, and y increases within a column.
+	for i := 1; i < len(got); i++ {
+		x0, y0, _ := got[i-1].XYZ()
+		x1, y1, _ := got[i].XYZ()
+		if x1 < x0 || (x1 == x0 && y1 <= y0) {
+			t.Fatalf("unexpected order at %d: (%d,%d) then (%d,%d)", i, x0, y0, x1, y1)
+		}
+	}
+
+	n := 0
+	for range IterKeysInBound(bound, 16) {
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	assertEqualInt(t, "early stop", n, 5)
+}
+
+func TestCountKeysInBound(t *testing.T) {
+	bounds := []orb.Bound{
+		{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}},
+		{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}},
+		{Min: orb.Point{5, 5}, Max: orb.Point{5, 5}},
+	}
+	for _, b := range bounds {
+		for _, z := range []int{1, 5, 10} {
+			if got, want := CountKeysInBound(b, z), int64(len(KeysInBound(b, z))); got != want {
+				t.Fatalf("count for %v at zoom %d: got %d, want %d", b, z, got, want)
+			}
+		}
+	}
+
+	world := orb.Bound{Min: orb.Point{-179.999999, -90}, Max: orb.Point{179.999999, 90}}
+	if got := CountKeysInBound(world, 20); got != 1<<40 {
+		t.Fatalf("world count at zoom 20: got %d, want %d", got, int64(1)<<40)
+	}
+	if got := CountKeysInBound(world, MaxZoom); got != math.MaxInt64 {
+		t.Fatalf("world count at zoom %d: got %d, want it to saturate", MaxZoom, got)
+	}
+}
+
+func TestKeysInBoundNonFinite(t *testing.T) {
+	for _, b := range []orb.Bound{
+		{Min: orb.Point{0, math.NaN()}, Max: orb.Point{10, 10}},
+		{Min: orb.Point{math.NaN(), 0}, Max: orb.Point{10, 10}},
+		{Min: orb.Point{0, 0}, Max: orb.Point{math.Inf(1), 10}},
+		{Min: orb.Point{0, math.Inf(-1)}, Max: orb.Point{10, 10}},
+	} {
+		if got := CountKeysInBound(b, 5); got != 0 {
+			t.Fatalf("%v: count %d, want 0", b, got)
+		}
+		got, err := KeysInBoundLimit(b, 5, 100)
+		if err != nil || len(got) != 0 {
+			t.Fatalf("%v: got %d keys, %v", b, len(got), err)
+		}
+		if got := KeysInBound(b, 5); len(got) != 0 {
+			t.Fatalf("%v: got %d keys", b, len(got))
+		}
+	}
+}
+
+func TestEnclosingKey(t *testing.T) {
+	for _, key := range []QuadKey{"1202", "13300211", "0231010101023"} {
+		got, err := EnclosingKey(key.Bound())
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", key, err)
+		}
+		if got != key {
+			t.Fatalf("bound of %q: got %q", key, got)
+		}
+	}
+
+	// A small bound inside one tile at zoom 12 is enclosed by a descendant of it.
+	key := FromLonLat(139.7, 35.6, 12)
+	c := key.Center()
+	small := orb.Bound{Min: orb.Point{c.Lon() - 0.001, c.Lat() - 0.001}, Max: orb.Point{c.Lon() + 0.001, c.Lat() + 0.001}}
+	got, err := EnclosingKey(small)
+	if err != nil {
+		t.Fatalf("small bound: unexpected error: %v", err)
+	}
+	if !key.IsAncestorOf(got) && got != key {
+		t.Fatalf("small bound: %q is not within %q", got, key)
+	}
+	for _, k := range KeysInBound(small, 16) {
+		if !got.IsAncestorOf(k) {
+			t.Fatalf("small bound: %q does not enclose %q", got, k)
+		}
+	}
+
+	// Straddling the equator and the prime meridian leaves only the world.
+	if _, err := EnclosingKey(orb.Bound{Min: orb.Point{-1, -1}, Max: orb.Point{1, 1}}); err == nil {
+		t.Fatalf("expected error for a bound crossing all four quadrants")
+	}
+	if _, err := EnclosingKey(orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{-1, -1}}); err == nil {
+		t.Fatalf("expected error for an inverted bound")
+	}
+}
+
+func TestEnclosingKeyForGeometry(t *testing.T) {
+	key := QuadKey("13300211")
+	nw := key.Corners()[0]
+	line := orb.LineString{{nw.Lon() + 1e-9, nw.Lat() - 1e-9}, key.Center()}
+	got, err := EnclosingKeyForGeometry(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != key {
+		t.Fatalf("line: got %q, want %q", got, key)
+	}
+
+	p := orb.Point{139.7, 35.6}
+	got, err = EnclosingKeyForGeometry(p)
+	if err != nil {
+		t.Fatalf("point: unexpected error: %v", err)
+	}
+	if got != FromPoint(p, got.Z()) || got.Z() != MaxZoom {
+		t.Fatalf("point: got %q", got)
+	}
+
+	for name, g := range map[string]orb.Geometry{"nil": nil, "empty": orb.Collection{}} {
+		if _, err := EnclosingKeyForGeometry(g); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestKeysInBoundCtx(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	want := KeysInBound(bound, 10)
+
+	got, err := KeysInBoundCtx(context.Background(), bound, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("keys differ from KeysInBound: got %d keys, want %d", len(got), len(want))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := KeysInBoundCtx(ctx, bound, 10); !errors.Is(err, context.Canceled) || got != nil {
+		t.Fatalf("canceled: got %d keys, err %v", len(got), err)
+	}
+
+	// A world-sized enumeration stops at the deadline instead of running on.
+	world := orb.Bound{Min: orb.Point{-179.999999, -90}, Max: orb.Point{179.999999, 90}}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := KeysInBoundCtx(ctx, world, 16); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBestZoom(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	for _, budget := range []int{1, 4, 100, 5000, 1000000} {
+		z := BestZoom(bound, budget)
+		if z > 0 && CountKeysInBound(bound, z) > int64(budget) {
+			t.Fatalf("budget %d: zoom %d has %d tiles", budget, z, CountKeysInBound(bound, z))
+		}
+		if z < MaxZoom && CountKeysInBound(bound, z+1) <= int64(budget) {
+			t.Fatalf("budget %d: zoom %d is not the deepest", budget, z)
+		}
+	}
+
+	world := orb.Bound{Min: orb.Point{-179.999999, -90}, Max: orb.Point{179.999999, 90}}
+	assertEqualInt(t, "world, 3 tiles", BestZoom(world, 3), 0)
+	assertEqualInt(t, "world, 4 tiles", BestZoom(world, 4), 1)
+	assertEqualInt(t, "world, 1<<20 tiles", BestZoom(world, 1<<20), 10)
+	assertEqualInt(t, "point", BestZoom(orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{1, 1}}, 1), MaxZoom)
+}
+
+func TestKeysInBoundProgress(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}}
+	count := CountKeysInBound(bound, 12)
+
+	calls := 0
+	var last int64
+	keys, err := KeysInBoundProgress(context.Background(), bound, 12, func(emitted, estimated int64) {
+		if emitted < last {
+			t.Errorf("emitted went back from %d to %d", last, emitted)
+		}
+		if estimated != count {
+			t.Errorf("estimate: got %d, want %d", estimated, count)
+		}
+		calls++
+		last = emitted
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls < 2 || last != int64(len(keys)) || last != count {
+		t.Fatalf("got %d calls ending at %d, want periodic calls ending at %d", calls, last, count)
+	}
+}
+
+func TestKeysInBoundLimit(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	want := KeysInBound(bound, 10)
+
+	got, err := KeysInBoundLimit(bound, 10, len(want))
+	if err != nil {
+		t.Fatalf("unexpected error at exact limit: %v", err)
+	}
+	assertEqualInt(t, "keys", len(got), len(want))
+
+	_, err = KeysInBoundLimit(bound, 10, len(want)-1)
+	if !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("expected ErrTooManyTiles, got %v", err)
+	}
+	var tooMany *TooManyTilesError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("expected *TooManyTilesError, got %T", err)
+	}
+	if tooMany.Count != int64(len(want)) || tooMany.Limit != len(want)-1 {
+		t.Fatalf("error details: got count=%d limit=%d", tooMany.Count, tooMany.Limit)
+	}
+
+	// A world-sized bound at a deep zoom fails fast instead of allocating.
+	world := orb.Bound{Min: orb.Point{-180, -85}, Max: orb.Point{180, 85}}
+	if _, err := KeysInBoundLimit(world, 24, 1000); !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("expected ErrTooManyTiles for world bound, got %v", err)
+	}
+}
+
+func TestIntersectsBoundMatchesKeysInBound(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139.5, 35.5}, Max: orb.Point{140.0, 36.0}}
+	zoom := 9
+
+	inside := map[QuadKey]bool{}
+	for _, k := range KeysInBound(bound, zoom) {
+		inside[k] = true
+	}
+
+	// Check every tile in a window around the bound.
+	for x := 450; x < 460; x++ {
+		for y := 195; y < 210; y++ {
+			k := FromXYZ(x, y, zoom)
+			if got := k.IntersectsBound(bound); got != inside[k] {
+				t.Fatalf("%q.IntersectsBound: got %v, KeysInBound says %v", k, got, inside[k])
+			}
+		}
+	}
+}
+
+func TestIntersectsBoundEdges(t *testing.T) {
+	key := QuadKey("13300221")
+	b := key.Bound()
+	c := key.Center()
+	if !key.IntersectsBound(b) {
+		t.Fatalf("tile should intersect its own bound")
+	}
+
+	// Bounds just west and just east of the tile stay clear of it.
+	west := orb.Bound{Min: orb.Point{b.Left() - 1, c.Lat()}, Max: orb.Point{b.Left() - 1e-9, c.Lat()}}
+	if key.IntersectsBound(west) {
+		t.Fatalf("bound west of the tile should not intersect")
+	}
+	east := orb.Bound{Min: orb.Point{b.Right() + 1e-9, c.Lat()}, Max: orb.Point{b.Right() + 1, c.Lat()}}
+	if key.IntersectsBound(east) {
+		t.Fatalf("bound east of the tile should not intersect")
+	}
+
+	// A bound crossing the east edge reaches the tile.
+	across := orb.Bound{Min: orb.Point{b.Right() - 1e-9, c.Lat()}, Max: orb.Point{b.Right() + 1, c.Lat()}}
+	if !key.IntersectsBound(across) {
+		t.Fatalf("bound crossing the east edge should intersect")
+	}
+
+	if QuadKey("01a3").IntersectsBound(b) {
+		t.Fatalf("invalid key should not intersect")
+	}
+}
+
+func TestContainsBound(t *testing.T) {
+	key := QuadKey("13300221")
+	b := key.Bound()
+
+	c := key.Center()
+	small := orb.Bound{Min: orb.Point{c.Lon() - 0.01, c.Lat() - 0.01}, Max: orb.Point{c.Lon() + 0.01, c.Lat() + 0.01}}
+	if !key.ContainsBound(small) {
+		t.Fatalf("tile should contain a small bound around its center")
+	}
+	if parent, _ := key.Parent(); !parent.ContainsBound(small) {
+		t.Fatalf("parent should contain the bound too")
+	}
+
+	wide := orb.Bound{Min: orb.Point{b.Left() - 0.5, c.Lat()}, Max: c}
+	if key.ContainsBound(wide) {
+		t.Fatalf("tile should not contain a bound extending past its west edge")
+	}
+	if QuadKey("01a3").ContainsBound(small) {
+		t.Fatalf("invalid key should contain nothing")
+	}
+}
+
 func TestToFeatureCollection(t *testing.T) {
 	k1 := QuadKey("0")
 	k2 := QuadKey("1")
@@ -219,3 +1115,170 @@ func TestToFeatureCollection(t *testing.T) {
 		t.Fatalf("feature[1].ID: got %v, want %v", fc.Features[1].ID, k2.String())
 	}
 }
+
+func TestToFeatureWithOptions(t *testing.T) {
+	key := QuadKey("0231")
+	if got := key.ToFeatureWithOptions(FeatureOptions{}); got.ID != "0231" || len(got.Properties) != 0 {
+		t.Fatalf("zero options: got ID %v, properties %v", got.ID, got.Properties)
+	}
+
+	opts := FeatureOptions{
+		ID:          FeatureIDQuadInt,
+		KeyProperty: "quadkey",
+		XYZ:         true,
+		Properties: func(key QuadKey) geojson.Properties {
+			return geojson.Properties{"fill": "#f00", "z": key.Z() * 10}
+		},
+	}
+	f := key.ToFeatureWithOptions(opts)
+	q, _ := key.QuadInt()
+	if f.ID != uint64(q) {
+		t.Fatalf("got ID %v, want %d", f.ID, q)
+	}
+	x, y, _ := key.XYZ()
+	want := geojson.Properties{"quadkey": "0231", "z": 40, "x": x, "y": y, "fill": "#f00"}
+	if !maps.Equal(f.Properties, want) {
+		t.Fatalf("got properties %v, want %v", f.Properties, want)
+	}
+
+	deep := QuadKey(strings.Repeat("1", 30))
+	if f := deep.ToFeatureWithOptions(opts); f.ID != nil {
+		t.Fatalf("expected no QuadInt ID at zoom 30, got %v", f.ID)
+	}
+	if f := key.ToFeatureWithOptions(FeatureOptions{ID: FeatureIDNone}); f.ID != nil {
+		t.Fatalf("expected no ID, got %v", f.ID)
+	}
+
+	fc := ToFeatureCollectionWithOptions(FeatureOptions{ID: FeatureIDNone, KeyProperty: "quadkey"}, "0", "13")
+	got, err := KeysFromFeatureCollection(fc)
+	if err != nil || !slices.Equal(got, []QuadKey{"0", "13"}) {
+		t.Fatalf("round trip: got %v, err %v", got, err)
+	}
+}
+
+func TestToLabelFeatureCollection(t *testing.T) {
+	fc := ToLabelFeatureCollection("0231", "01a", "3")
+	if len(fc.Features) != 2 {
+		t.Fatalf("feature count: got %d, want 2", len(fc.Features))
+	}
+	f := fc.Features[0]
+	if f.Geometry != QuadKey("0231").Center() {
+		t.Fatalf("got geometry %v, want the tile center", f.Geometry)
+	}
+	want := geojson.Properties{"quadkey": "0231", "z": 4, "x": 3, "y": 6}
+	if f.ID != "0231" || !maps.Equal(f.Properties, want) {
+		t.Fatalf("got ID %v, properties %v, want %v", f.ID, f.Properties, want)
+	}
+
+	// Labels read back like any collection.
+	if got, err := KeysFromFeatureCollection(fc); err != nil || !slices.Equal(got, []QuadKey{"0231", "3"}) {
+		t.Fatalf("round trip: got %v, err %v", got, err)
+	}
+}
+
+func TestKeysFromFeatureCollection(t *testing.T) {
+	keys := []QuadKey{"0123", "1", "3302"}
+	fc := ToFeatureCollection(keys...)
+
+	// A feature without an ID falls back to the quadkey property.
+	byProperty := geojson.NewFeature(orb.Point{0, 0})
+	byProperty.Properties["quadkey"] = "213"
+	fc.Append(byProperty)
+
+	got, err := KeysFromFeatureCollection(fc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := append(keys, "213"); !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Round trip through GeoJSON bytes.
+	data, err := fc.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded, err := geojson.UnmarshalFeatureCollection(data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got, err := KeysFromFeatureCollection(decoded); err != nil || len(got) != 4 {
+		t.Fatalf("decoded: got %v, err %v", got, err)
+	}
+
+	missing := geojson.NewFeatureCollection().Append(geojson.NewFeature(orb.Point{0, 0}))
+	if _, err := KeysFromFeatureCollection(missing); err == nil {
+		t.Fatalf("expected error for a feature without a key")
+	}
+	invalid := ToFeatureCollection("01a3")
+	if _, err := KeysFromFeatureCollection(invalid); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if got, err := KeysFromFeatureCollection(nil); err != nil || len(got) != 0 {
+		t.Fatalf("nil collection: got %v, err %v", got, err)
+	}
+}
+
+func TestFromPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	pts := []orb.Point{{-180, 0}, {180, 0}, {540, 10}, {-200, -86}, {0, 90}, {139.7671, 35.6812}}
+	for range 1000 {
+		pts = append(pts, orb.Point{rng.Float64()*400 - 200, rng.Float64()*190 - 95})
+	}
+	for _, zoom := range []int{1, 7, 18, 30} {
+		got := FromPoints(pts, zoom)
+		if len(got) != len(pts) {
+			t.Fatalf("zoom %d: got %d keys, want %d", zoom, len(got), len(pts))
+		}
+		for i, p := range pts {
+			if want := FromPoint(p, zoom); got[i] != want {
+				t.Fatalf("zoom %d, %v: got %q, want %q", zoom, p, got[i], want)
+			}
+		}
+	}
+
+	dst := []QuadKey{"0"}
+	dst = AppendFromPoints(dst, pts[:3], 4)
+	if len(dst) != 4 || dst[0] != "0" || dst[3] != FromPoint(pts[2], 4) {
+		t.Fatalf("append: got %v", dst)
+	}
+	if got := FromPoints(pts[:2], 0); len(got) != 2 || got[0] != "" {
+		t.Fatalf("zoom 0: got %v", got)
+	}
+}
+
+func TestFromXYZChecked(t *testing.T) {
+	if got, err := FromXYZChecked(3, 6, 4); err != nil || got != "0231" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if got, err := FromXYZChecked(1<<30-1, 1<<30-1, 30); err != nil || got != QuadKey(strings.Repeat("3", 30)) {
+		t.Fatalf("zoom 30 corner: got %q, %v", got, err)
+	}
+	for _, tc := range [][3]int{{0, 0, 0}, {0, 0, -1}, {0, 0, 33}, {-1, 0, 4}, {0, -1, 4}, {16, 0, 4}, {0, 16, 4}} {
+		if got, err := FromXYZChecked(tc[0], tc[1], tc[2]); err == nil {
+			t.Fatalf("%v: expected an error, got %q", tc, got)
+		}
+	}
+}
+
+func TestFromXYZAppend(t *testing.T) {
+	buf := []byte("key=")
+	buf = FromXYZAppend(buf, 3, 6, 4)
+	if string(buf) != "key=0231" {
+		t.Fatalf("got %q, want %q", buf, "key=0231")
+	}
+	buf = QuadKey("13").AppendTo(append(buf, ','))
+	if string(buf) != "key=0231,13" {
+		t.Fatalf("got %q", buf)
+	}
+
+	// Reusing a buffer allocates nothing.
+	buf = make([]byte, 0, 32)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = FromXYZAppend(buf[:0], 227, 100, 8)
+		buf = QuadKey("13300211").AppendTo(buf)
+	})
+	if allocs != 0 || string(buf) != "1330021113300211" {
+		t.Fatalf("got %q with %v allocations", buf, allocs)
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/nideojp/go-quadkey/quadkeyh3
+
+go 1.25.5
+
+require (
+	github.com/nideojp/go-quadkey v0.0.0
+	github.com/paulmach/orb v0.12.0
+	github.com/uber/h3-go/v4 v4.5.0
+)
+
+require go.mongodb.org/mongo-driver v1.11.4 // indirect
+
+replace github.com/nideojp/go-quadkey => ../
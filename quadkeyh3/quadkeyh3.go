@@ -0,0 +1,158 @@
+// Package quadkeyh3 bridges quadkeys and Uber H3 cells. It lives in its own
+// module so that only users of H3 pull in its cgo dependency.
+//
+// Tiles and hexagons never line up, so both directions return covers: every
+// cell of the other grid overlapping the input. Tile edges follow parallels
+// and meridians while H3 edges follow great circles, so each side's edges
+// are densified along their own curves before conversion: H3 edges along
+// great circles and tile edges in lon/lat. The covers follow the true edges
+// closely, but a tile touched only by the sliver between an edge and its
+// densified chords can still be missed.
+package quadkeyh3
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/clip"
+	"github.com/uber/h3-go/v4"
+)
+
+// edgeSteps is the number of segments every edge is split into.
+const edgeSteps = 8
+
+// --------------------------
+// H3 interop
+// --------------------------
+
+// KeysCoveringH3Cell returns the keys at zoom overlapping the H3 cell, sorted
+// in quadkey order. Cells crossing the antimeridian are split along it, and
+// cells around a pole are closed through it. An error is returned for an
+// invalid cell or zoom.
+func KeysCoveringH3Cell(cell h3.Cell, zoom int) ([]quadkey.QuadKey, error) {
+	if !cell.IsValid() {
+		return nil, fmt.Errorf("invalid h3 cell %s", cell)
+	}
+	if zoom < 1 || zoom > quadkey.MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, quadkey.MaxZoom)
+	}
+	boundary, err := cell.Boundary()
+	if err != nil {
+		return nil, err
+	}
+
+	ring := make(orb.Ring, 0, len(boundary)+1)
+	for _, ll := range boundary {
+		ring = append(ring, orb.Point{ll.Lng, ll.Lat})
+	}
+	ring = densifyArcs(append(ring, ring[0]))
+
+	// Unwrap longitudes so the ring is continuous across the antimeridian.
+	for i := 1; i < len(ring); i++ {
+		ring[i][0] = ring[i-1][0] + math.Remainder(ring[i][0]-ring[i-1][0], 360)
+	}
+
+	// A ring around a pole ends a full turn from where it started. Close it
+	// through the pole, along the meridians at both ends.
+	first, last := ring[0], ring[len(ring)-1]
+	if math.Abs(last[0]-first[0]) > 180 {
+		pole := 90.0
+		if center, err := cell.LatLng(); err == nil && center.Lat < 0 {
+			pole = -90
+		}
+		ring = append(ring, orb.Point{last[0], pole}, orb.Point{first[0], pole}, first)
+	}
+
+	// Cover the parts of the ring in each copy of the world separately.
+	keys := []quadkey.QuadKey{}
+	for _, shift := range []float64{0, -360, 360} {
+		part := clip.Polygon(orb.Bound{Min: orb.Point{-180 - shift, -90}, Max: orb.Point{180 - shift, 90}}, orb.Polygon{ring})
+		if len(part) == 0 || len(part[0]) == 0 {
+			continue
+		}
+		for i := range part[0] {
+			// Lon -180 reads as +180 when covered, so cut edges are kept
+			// just inside the western half.
+			part[0][i][0] = max(part[0][i][0]+shift, math.Nextafter(-180, 0))
+		}
+		keys = append(keys, quadkey.Cover(part, zoom)...)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
+
+// H3CellsCoveringKey returns the H3 cells at res overlapping the key's tile,
+// sorted by index. An error is returned for an invalid key or resolution.
+func H3CellsCoveringKey(key quadkey.QuadKey, res int) ([]h3.Cell, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	if res < 0 || res > h3.MaxResolution {
+		return nil, fmt.Errorf("resolution %d is out of range [0, %d]", res, h3.MaxResolution)
+	}
+
+	ring := densify(key.ToRing())
+	loop := make(h3.GeoLoop, 0, len(ring)-1)
+	for _, p := range ring[:len(ring)-1] {
+		loop = append(loop, h3.NewLatLng(p.Lat(), p.Lon()))
+	}
+	cells, err := h3.PolygonToCellsExperimental(h3.GeoPolygon{GeoLoop: loop}, res, h3.ContainmentOverlapping)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return nil, errors.New("no h3 cells overlap the key")
+	}
+	slices.Sort(cells)
+	return slices.Compact(cells), nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// densifyArcs splits every edge of a closed ring into edgeSteps segments
+// along the great circle through its ends.
+func densifyArcs(ring orb.Ring) orb.Ring {
+	out := make(orb.Ring, 0, (len(ring)-1)*edgeSteps+1)
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := unitVector(ring[i]), unitVector(ring[i+1])
+		angle := math.Acos(max(-1, min(1, a[0]*b[0]+a[1]*b[1]+a[2]*b[2])))
+		for s := range edgeSteps {
+			if angle == 0 || s == 0 {
+				out = append(out, ring[i])
+				continue
+			}
+			// Spherical linear interpolation between the two ends.
+			t := float64(s) / edgeSteps
+			wa := math.Sin((1-t)*angle) / math.Sin(angle)
+			wb := math.Sin(t*angle) / math.Sin(angle)
+			x, y, z := wa*a[0]+wb*b[0], wa*a[1]+wb*b[1], wa*a[2]+wb*b[2]
+			out = append(out, orb.Point{math.Atan2(y, x) * 180 / math.Pi, math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi})
+		}
+	}
+	return append(out, ring[len(ring)-1])
+}
+
+// unitVector returns the point on the unit sphere at lon/lat p.
+func unitVector(p orb.Point) [3]float64 {
+	lon, lat := p.Lon()*math.Pi/180, p.Lat()*math.Pi/180
+	return [3]float64{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+// densify splits every edge of a closed ring into edgeSteps segments.
+func densify(ring orb.Ring) orb.Ring {
+	out := make(orb.Ring, 0, (len(ring)-1)*edgeSteps+1)
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i], ring[i+1]
+		for s := range edgeSteps {
+			t := float64(s) / edgeSteps
+			out = append(out, orb.Point{a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t})
+		}
+	}
+	return append(out, ring[len(ring)-1])
+}
@@ -0,0 +1,119 @@
+package quadkeyh3
+
+import (
+	"slices"
+	"testing"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+	"github.com/uber/h3-go/v4"
+)
+
+func TestKeysCoveringH3Cell(t *testing.T) {
+	cell, err := h3.LatLngToCell(h3.NewLatLng(35.681236, 139.767125), 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err := KeysCoveringH3Cell(cell, 15)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) == 0 || !slices.IsSorted(keys) {
+		t.Fatalf("expected sorted keys, got %v", keys)
+	}
+
+	// Every vertex and the center of the cell fall in a covering tile.
+	boundary, _ := cell.Boundary()
+	center, _ := cell.LatLng()
+	for _, ll := range append(boundary, center) {
+		k := quadkey.FromPoint(orb.Point{ll.Lng, ll.Lat}, 15)
+		if !slices.Contains(keys, k) {
+			t.Fatalf("point %v (%q) is not covered", ll, k)
+		}
+	}
+}
+
+func TestKeysCoveringH3CellAntimeridian(t *testing.T) {
+	cell, _ := h3.LatLngToCell(h3.NewLatLng(0, 180), 2)
+	keys, err := KeysCoveringH3Cell(cell, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	east, west := false, false
+	for _, k := range keys {
+		c := k.Center()
+		east = east || c.Lon() > 170
+		west = west || c.Lon() < -170
+		if c.Lon() > -170 && c.Lon() < 170 {
+			t.Fatalf("%q at %v is far from the antimeridian", k, c)
+		}
+	}
+	if !east || !west {
+		t.Fatalf("expected tiles on both sides of the antimeridian, got %v", keys)
+	}
+}
+
+func TestKeysCoveringH3CellPolar(t *testing.T) {
+	// Res 0 cells around a pole, or next to one across the antimeridian.
+	for _, ll := range []h3.LatLng{h3.NewLatLng(84, 0), h3.NewLatLng(80, 179.9), h3.NewLatLng(-84, 0), h3.NewLatLng(-80, -179.9)} {
+		cell, err := h3.LatLngToCell(ll, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys, err := KeysCoveringH3Cell(cell, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, k := range keys {
+			if c := k.Center(); c.Lat()*ll.Lat < 0 {
+				t.Fatalf("cell %s at %v: tile %q at %v is in the other hemisphere", cell, ll, k, c)
+			}
+		}
+
+		// Every tile whose center H3 places in the cell must be covered.
+		for x := range 32 {
+			for y := range 32 {
+				k := quadkey.FromXYZ(x, y, 5)
+				c := k.Center()
+				if got, _ := h3.LatLngToCell(h3.NewLatLng(c.Lat(), c.Lon()), 0); got == cell && !slices.Contains(keys, k) {
+					t.Fatalf("cell %s at %v: tile %q centered at %v is not covered", cell, ll, k, c)
+				}
+			}
+		}
+	}
+}
+
+func TestH3CellsCoveringKey(t *testing.T) {
+	key := quadkey.FromLonLat(139.767125, 35.681236, 12)
+	cells, err := H3CellsCoveringKey(key, 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.IsSorted(cells) {
+		t.Fatalf("expected sorted cells")
+	}
+	corners := key.Corners()
+	for _, p := range append(corners[:], key.Center()) {
+		c, _ := h3.LatLngToCell(h3.NewLatLng(p.Lat(), p.Lon()), 8)
+		if !slices.Contains(cells, c) {
+			t.Fatalf("corner %v (%s) is not covered", p, c)
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	if _, err := KeysCoveringH3Cell(h3.Cell(0), 10); err == nil {
+		t.Fatalf("expected error for an invalid cell")
+	}
+	cell, _ := h3.LatLngToCell(h3.NewLatLng(0, 0), 5)
+	if _, err := KeysCoveringH3Cell(cell, 0); err == nil {
+		t.Fatalf("expected error for zoom 0")
+	}
+	if _, err := H3CellsCoveringKey("01a", 5); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := H3CellsCoveringKey("0123", 16); err == nil {
+		t.Fatalf("expected error for resolution 16")
+	}
+}
@@ -0,0 +1,108 @@
+package quadkeyhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// debug grid server
+// --------------------------
+
+// maxDebugGridKeys caps the tiles a /grid request may return, so a stray
+// zoom cannot make the server build millions of features.
+const maxDebugGridKeys = 10000
+
+// DebugHandler returns a handler serving tiles as GeoJSON for inspecting
+// coverages live in geojson.io or QGIS during development:
+//
+//	GET /grid?bbox=west,south,east,north&z=zoom  tiles of the bound, as KeysInBound
+//	GET /key/{quadkey}.geojson                   one tile as a Feature
+//
+// Grids are capped at 10000 tiles. Responses allow any origin, so browser
+// tools on other hosts can fetch them. Mount it below a prefix with
+// http.StripPrefix. It is meant for development, not production traffic.
+func DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /grid", serveDebugGrid)
+	mux.HandleFunc("GET /key/{key}", serveDebugKey)
+	return mux
+}
+
+func serveDebugGrid(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	bound, err := parseBBox(q.Get("bbox"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoom, err := strconv.Atoi(q.Get("z"))
+	if err != nil || zoom < 1 || zoom > quadkey.MaxZoom {
+		http.Error(w, fmt.Sprintf("zoom %q is out of range [1, %d]", q.Get("z"), quadkey.MaxZoom), http.StatusBadRequest)
+		return
+	}
+	keys, err := quadkey.KeysInBoundLimit(bound, zoom, maxDebugGridKeys)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeGeoJSON(w, quadkey.ToFeatureCollection(keys...))
+}
+
+func serveDebugKey(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("key"), ".geojson")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	key, err := quadkey.FromKey(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeGeoJSON(w, key.ToFeature())
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// parseBBox parses "west,south,east,north". Every value must be finite, and
+// south must not lie north of north.
+func parseBBox(s string) (orb.Bound, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return orb.Bound{}, fmt.Errorf("bbox %q is not west,south,east,north", s)
+	}
+	var v [4]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return orb.Bound{}, fmt.Errorf("bbox %q is not west,south,east,north", s)
+		}
+		v[i] = f
+	}
+	if v[1] > v[3] {
+		return orb.Bound{}, fmt.Errorf("bbox %q has south above north", s)
+	}
+	return orb.Bound{Min: orb.Point{v[0], v[1]}, Max: orb.Point{v[2], v[3]}}, nil
+}
+
+// writeGeoJSON writes v, a Feature or FeatureCollection, as the response.
+func writeGeoJSON(w http.ResponseWriter, v json.Marshaler) {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(data)
+}
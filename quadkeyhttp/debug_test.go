@@ -0,0 +1,67 @@
+package quadkeyhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestDebugHandler(t *testing.T) {
+	h := DebugHandler()
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/grid?bbox=-10,-10,10,10&z=4")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("grid: got status %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/geo+json" {
+		t.Fatalf("grid: got content type %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("grid: got allowed origin %q", got)
+	}
+	fc, err := geojson.UnmarshalFeatureCollection(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("grid: %v", err)
+	}
+	want := quadkey.KeysInBound(orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}}, 4)
+	if len(fc.Features) != len(want) || fc.Features[0].ID != string(want[0]) {
+		t.Fatalf("grid: got %d features, want %v", len(fc.Features), want)
+	}
+
+	rec = get("/key/0231.geojson")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("key: got status %d: %s", rec.Code, rec.Body)
+	}
+	f, err := geojson.UnmarshalFeature(rec.Body.Bytes())
+	if err != nil || f.ID != "0231" || !orb.Equal(f.Geometry, quadkey.QuadKey("0231").Bound().ToPolygon()) {
+		t.Fatalf("key: got %v, err %v", f, err)
+	}
+
+	for target, status := range map[string]int{
+		"/grid?bbox=-10,-10,10&z=4":       http.StatusBadRequest,
+		"/grid?bbox=a,b,c,d&z=4":          http.StatusBadRequest,
+		"/grid?bbox=0,NaN,10,10&z=5":      http.StatusBadRequest,
+		"/grid?bbox=0,0,Inf,10&z=5":       http.StatusBadRequest,
+		"/grid?bbox=-inf,0,10,10&z=5":     http.StatusBadRequest,
+		"/grid?bbox=0,10,10,0&z=5":        http.StatusBadRequest,
+		"/grid?bbox=-10,-10,10,10":        http.StatusBadRequest,
+		"/grid?bbox=-10,-10,10,10&z=33":   http.StatusBadRequest,
+		"/grid?bbox=-170,-80,170,80&z=12": http.StatusBadRequest,
+		"/key/02a1.geojson":               http.StatusBadRequest,
+		"/key/0231":                       http.StatusNotFound,
+		"/other":                          http.StatusNotFound,
+	} {
+		if rec := get(target); rec.Code != status {
+			t.Fatalf("%s: got status %d, want %d", target, rec.Code, status)
+		}
+	}
+}
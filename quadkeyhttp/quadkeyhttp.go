@@ -0,0 +1,86 @@
+// Package quadkeyhttp provides HTTP glue for tile endpoints: it parses the
+// tile addressed by a request path and hands it to a handler as a QuadKey.
+// It also serves tile grids as GeoJSON for debugging; see DebugHandler.
+package quadkeyhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	quadkey "github.com/nideojp/go-quadkey"
+)
+
+// --------------------------
+// path parsing
+// --------------------------
+
+// ParsePath parses a tile path relative to the route prefix: either
+// "{z}/{x}/{y}" or "{quadkey}", optionally with a file extension such as
+// ".png" or ".pbf" on the last segment. Surrounding slashes are ignored.
+func ParsePath(path string) (quadkey.QuadKey, error) {
+	path = strings.Trim(path, "/")
+	switch segs := strings.Split(path, "/"); len(segs) {
+	case 1:
+		if segs[0] == "" {
+			return "", errors.New("tile path is empty")
+		}
+		key, _, _ := strings.Cut(segs[0], ".")
+		return quadkey.FromKey(key)
+	case 3:
+		return quadkey.FromTilePath(path)
+	default:
+		return "", fmt.Errorf("tile path %q is neither z/x/y nor a quadkey", path)
+	}
+}
+
+// --------------------------
+// middleware
+// --------------------------
+
+type contextKey struct{}
+
+// Middleware parses the tile from the request path below prefix, such as
+// "/tiles/" for "/tiles/{z}/{x}/{y}" or "/tiles/{quadkey}", and calls next
+// with the key stored in the request context; see FromContext. Paths outside
+// prefix get 404 Not Found and malformed tile paths 400 Bad Request.
+func Middleware(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		key, err := ParsePath(rest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), key)))
+	})
+}
+
+// HandlerFunc is a handler of tile requests.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request, key quadkey.QuadKey)
+
+// Handle returns a handler serving tile requests below prefix with fn, as
+// Middleware does.
+func Handle(prefix string, fn HandlerFunc) http.Handler {
+	return Middleware(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, _ := FromContext(r.Context())
+		fn(w, r, key)
+	}))
+}
+
+// NewContext returns a copy of ctx carrying key.
+func NewContext(ctx context.Context, key quadkey.QuadKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key stored by Middleware, if any.
+func FromContext(ctx context.Context) (quadkey.QuadKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(quadkey.QuadKey)
+	return key, ok
+}
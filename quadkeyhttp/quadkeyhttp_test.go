@@ -0,0 +1,62 @@
+package quadkeyhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	quadkey "github.com/nideojp/go-quadkey"
+)
+
+func TestParsePath(t *testing.T) {
+	want := quadkey.FromXYZ(3639, 1612, 12)
+	for _, p := range []string{"12/3639/1612", "/12/3639/1612.png", "12/3639/1612.pbf/", string(want), "/" + string(want) + ".png"} {
+		got, err := ParsePath(p)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %q, err %v, want %q", p, got, err, want)
+		}
+	}
+	for _, p := range []string{"", "/", "12/3639", "a/b/c", "0/0/0", "3/8/0", "0124", "x/12/3639/1612"} {
+		if got, err := ParsePath(p); err == nil {
+			t.Fatalf("%q: expected error, got %q", p, got)
+		}
+	}
+}
+
+func TestHandle(t *testing.T) {
+	h := Handle("/tiles/", func(w http.ResponseWriter, r *http.Request, key quadkey.QuadKey) {
+		w.Write([]byte(key))
+	})
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/tiles/1/0/1.png", http.StatusOK, "2"},
+		{"/tiles/1202", http.StatusOK, "1202"},
+		{"/tiles/1/2/0", http.StatusBadRequest, ""},
+		{"/tiles/12a", http.StatusBadRequest, ""},
+		{"/other/1/0/1", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Fatalf("%s: got status %d, want %d", tt.path, rec.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+			t.Fatalf("%s: got body %q, want %q", tt.path, rec.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("expected no key in an empty context")
+	}
+	key, ok := FromContext(NewContext(context.Background(), "0123"))
+	if !ok || key != "0123" {
+		t.Fatalf("got %q, %v", key, ok)
+	}
+}
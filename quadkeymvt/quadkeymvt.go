@@ -0,0 +1,96 @@
+// Package quadkeymvt renders key coverages as Mapbox Vector Tile layers, so
+// map frontends can draw coverage grids tile by tile instead of loading one
+// large GeoJSON FeatureCollection.
+package quadkeymvt
+
+import (
+	"fmt"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/mvt"
+	"github.com/paulmach/orb/geojson"
+)
+
+// PropertiesFunc returns the extra properties of a key's feature. It may
+// return nil.
+type PropertiesFunc func(key quadkey.QuadKey) geojson.Properties
+
+// --------------------------
+// layers
+// --------------------------
+
+// Layer returns an MVT layer named name, for the vector tile tile, holding
+// one polygon feature per key that overlaps the tile. Each feature has a
+// "quadkey" property and, for keys up to quadkey.MaxQuadIntZoom, its
+// QuadInt as the feature ID. Keys coarser than the tile cover all of it.
+// Keys more than 12 zooms deeper than the tile are smaller than one unit of
+// the default 4096 extent and collapse when encoded.
+//
+// Geometries are computed in tile space from the keys' tile coordinates, so
+// they are exact, need no clipping and line up with neighboring tiles.
+// props, if not nil, adds properties to every feature.
+func Layer(name string, tile quadkey.QuadKey, keys []quadkey.QuadKey, props PropertiesFunc) (*mvt.Layer, error) {
+	if err := tile.Valid(); err != nil {
+		return nil, err
+	}
+	tx, ty, tz := tile.XYZ()
+
+	layer := mvt.NewLayer(name, geojson.NewFeatureCollection())
+	layer.Version = 2
+	extent := float64(layer.Extent)
+	for _, key := range keys {
+		if err := key.Valid(); err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		var ring orb.Ring
+		switch {
+		case key.Z() <= tz:
+			if !key.IsAncestorOf(tile) && key != tile {
+				continue
+			}
+			ring = square(0, 0, extent)
+		default:
+			if !key.IsDescendantOf(tile) {
+				continue
+			}
+			x, y, z := key.XYZ()
+			n := 1 << (z - tz)
+			size := extent / float64(n)
+			ring = square(float64(x-tx*n)*size, float64(y-ty*n)*size, size)
+		}
+
+		f := geojson.NewFeature(orb.Polygon{ring})
+		if props != nil {
+			for k, v := range props(key) {
+				f.Properties[k] = v
+			}
+		}
+		f.Properties["quadkey"] = string(key)
+		if q, err := key.QuadInt(); err == nil {
+			f.ID = uint64(q)
+		}
+		layer.Features = append(layer.Features, f)
+	}
+	return layer, nil
+}
+
+// Marshal encodes a vector tile holding a single Layer.
+func Marshal(name string, tile quadkey.QuadKey, keys []quadkey.QuadKey, props PropertiesFunc) ([]byte, error) {
+	layer, err := Layer(name, tile, keys, props)
+	if err != nil {
+		return nil, err
+	}
+	return mvt.Marshal(mvt.Layers{layer})
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// square returns the closed ring of a square in tile space, whose y axis
+// points down. The winding is clockwise on screen, which MVT requires of
+// exterior rings.
+func square(x, y, size float64) orb.Ring {
+	return orb.Ring{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}
+}
@@ -0,0 +1,86 @@
+package quadkeymvt
+
+import (
+	"testing"
+
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/mvt"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestLayer(t *testing.T) {
+	keys := []quadkey.QuadKey{"1", "120", "1203", "12031", "2"}
+	layer, err := Layer("coverage", "120", keys, func(key quadkey.QuadKey) geojson.Properties {
+		return geojson.Properties{"zoom": key.Z()}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// "2" lies outside the tile.
+	if len(layer.Features) != 4 {
+		t.Fatalf("expected 4 features, got %d", len(layer.Features))
+	}
+
+	want := map[string]orb.Bound{
+		"1":     {Min: orb.Point{0, 0}, Max: orb.Point{4096, 4096}},
+		"120":   {Min: orb.Point{0, 0}, Max: orb.Point{4096, 4096}},
+		"1203":  {Min: orb.Point{2048, 2048}, Max: orb.Point{4096, 4096}},
+		"12031": {Min: orb.Point{3072, 2048}, Max: orb.Point{4096, 3072}},
+	}
+	for _, f := range layer.Features {
+		key := f.Properties.MustString("quadkey")
+		if got := f.Geometry.Bound(); got != want[key] {
+			t.Fatalf("%q: got bound %v, want %v", key, got, want[key])
+		}
+		if f.Properties["zoom"] != len(key) {
+			t.Fatalf("%q: got zoom property %v", key, f.Properties["zoom"])
+		}
+		q, _ := quadkey.QuadKey(key).QuadInt()
+		if f.ID != uint64(q) {
+			t.Fatalf("%q: got id %v, want %d", key, f.ID, q)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	tile := quadkey.FromLonLat(139.767125, 35.681236, 10)
+	keys := []quadkey.QuadKey{}
+	for key := range tile.DescendantsAtZoom(12) {
+		keys = append(keys, key)
+	}
+	data, err := Marshal("grid", tile, keys, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	layers, err := mvt.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(layers) != 1 || layers[0].Name != "grid" || len(layers[0].Features) != 16 {
+		t.Fatalf("unexpected layers %+v", layers)
+	}
+
+	// Decoded back to lon/lat, each feature covers its key's tile.
+	layers.ProjectToWGS84(tile.MapTile())
+	for _, f := range layers[0].Features {
+		key := quadkey.QuadKey(f.Properties.MustString("quadkey"))
+		poly, ok := f.Geometry.(orb.Polygon)
+		if !ok {
+			t.Fatalf("%q: got %T, want a polygon", key, f.Geometry)
+		}
+		if c := poly.Bound().Center(); quadkey.FromPoint(c, 12) != key {
+			t.Fatalf("%q: feature centered at %v", key, c)
+		}
+	}
+}
+
+func TestLayerInvalid(t *testing.T) {
+	if _, err := Layer("l", "01a", nil, nil); err == nil {
+		t.Fatalf("expected error for an invalid tile")
+	}
+	if _, err := Layer("l", "01", []quadkey.QuadKey{"01x"}, nil); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+}
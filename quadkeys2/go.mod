@@ -0,0 +1,13 @@
+module github.com/nideojp/go-quadkey/quadkeys2
+
+go 1.25.5
+
+require (
+	github.com/golang/geo v0.0.0-20260818125358-b200a1149890
+	github.com/nideojp/go-quadkey v0.0.0
+	github.com/paulmach/orb v0.12.0
+)
+
+require go.mongodb.org/mongo-driver v1.11.4 // indirect
+
+replace github.com/nideojp/go-quadkey => ../
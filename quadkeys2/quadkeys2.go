@@ -0,0 +1,121 @@
+// Package quadkeys2 bridges quadkeys and Google S2 cells. It lives in its own
+// module so that only users of S2 pull in its dependency.
+//
+// Tiles and S2 cells never line up, so both directions return covers: every
+// cell of the other grid overlapping the input. A tile is a latitude-longitude
+// rectangle, which S2 represents exactly, so overlaps are tested exactly,
+// ignoring contact along a shared edge.
+package quadkeys2
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+)
+
+// edgeMargin, in radians, is how far tile rectangles are shrunk before they
+// are tested, so tiles and cells that only share an edge do not overlap. It
+// is under a millimeter on the ground, and tileRect caps it at a quarter of
+// the tile's extent so the deepest tiles never invert.
+const edgeMargin = 1e-10
+
+// --------------------------
+// S2 interop
+// --------------------------
+
+// KeysCoveringS2Cell returns the keys at zoom overlapping the S2 cell, sorted
+// in quadkey order. Candidates come from the cell's lat/lng bound, split at
+// the antimeridian, and each is tested against the cell exactly. An error is
+// returned for an invalid cell or zoom.
+func KeysCoveringS2Cell(id s2.CellID, zoom int) ([]quadkey.QuadKey, error) {
+	if !id.IsValid() {
+		return nil, fmt.Errorf("invalid s2 cell %v", id)
+	}
+	if zoom < 1 || zoom > quadkey.MaxZoom {
+		return nil, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, quadkey.MaxZoom)
+	}
+
+	cell := s2.CellFromCellID(id)
+	rb := cell.RectBound()
+	south, north := rb.Lat.Lo*180/math.Pi, rb.Lat.Hi*180/math.Pi
+	west, east := rb.Lng.Lo*180/math.Pi, rb.Lng.Hi*180/math.Pi
+	lngs := [][2]float64{{west, east}}
+	if rb.Lng.IsInverted() {
+		lngs = [][2]float64{{west, 180}, {-180, east}}
+	}
+
+	keys := []quadkey.QuadKey{}
+	for _, lng := range lngs {
+		// Lon -180 reads as +180 in a bound, so the western edge is kept
+		// just inside the map.
+		bound := orb.Bound{
+			Min: orb.Point{max(lng[0], math.Nextafter(-180, 0)), south},
+			Max: orb.Point{lng[1], north},
+		}
+		for key := range quadkey.IterKeysInBound(bound, zoom) {
+			if tileRect(key).IntersectsCell(cell) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
+
+// S2CellsCoveringKey returns the S2 cells at level overlapping the key's
+// tile, sorted by id. An error is returned for an invalid key or level.
+func S2CellsCoveringKey(key quadkey.QuadKey, level int) ([]s2.CellID, error) {
+	if err := key.Valid(); err != nil {
+		return nil, err
+	}
+	if level < 0 || level > s2.MaxLevel {
+		return nil, fmt.Errorf("level %d is out of range [0, %d]", level, s2.MaxLevel)
+	}
+
+	rect := tileRect(key)
+	ids := []s2.CellID{}
+	var walk func(c s2.Cell)
+	walk = func(c s2.Cell) {
+		if !rect.IntersectsCell(c) {
+			return
+		}
+		if c.Level() == level {
+			ids = append(ids, c.ID())
+			return
+		}
+		children, _ := c.Children()
+		for _, child := range children {
+			walk(child)
+		}
+	}
+	for face := range 6 {
+		walk(s2.CellFromCellID(s2.CellIDFromFace(face)))
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// tileRect returns the key's tile as an S2 lat/lng rectangle, shrunk on each
+// axis by edgeMargin or a quarter of its extent, whichever is smaller.
+func tileRect(key quadkey.QuadKey) s2.Rect {
+	b := key.Bound()
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	south, north := rad(b.Min.Lat()), rad(b.Max.Lat())
+	west, east := rad(b.Min.Lon()), rad(b.Max.Lon())
+	latMargin := min(edgeMargin, (north-south)/4)
+	lngMargin := min(edgeMargin, (east-west)/4)
+	return s2.Rect{
+		Lat: r1.Interval{Lo: south + latMargin, Hi: north - latMargin},
+		Lng: s1.IntervalFromEndpoints(west+lngMargin, east-lngMargin),
+	}
+}
@@ -0,0 +1,129 @@
+package quadkeys2
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	quadkey "github.com/nideojp/go-quadkey"
+	"github.com/paulmach/orb"
+)
+
+func TestKeysCoveringS2Cell(t *testing.T) {
+	id := s2.CellIDFromLatLng(s2.LatLngFromDegrees(35.681236, 139.767125)).Parent(12)
+	keys, err := KeysCoveringS2Cell(id, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) == 0 || !slices.IsSorted(keys) {
+		t.Fatalf("expected sorted keys, got %v", keys)
+	}
+
+	// Every vertex and the center of the cell fall in a covering tile.
+	cell := s2.CellFromCellID(id)
+	points := []s2.Point{cell.Center()}
+	for i := range 4 {
+		points = append(points, cell.Vertex(i))
+	}
+	for _, p := range points {
+		ll := s2.LatLngFromPoint(p)
+		k := quadkey.FromPoint(orb.Point{ll.Lng.Degrees(), ll.Lat.Degrees()}, 16)
+		if !slices.Contains(keys, k) {
+			t.Fatalf("point %v (%q) is not covered", ll, k)
+		}
+	}
+}
+
+func TestKeysCoveringS2CellAntimeridian(t *testing.T) {
+	// Face 3 is centered on the antimeridian.
+	keys, err := KeysCoveringS2Cell(s2.CellIDFromFace(3), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	east, west := false, false
+	for _, k := range keys {
+		c := k.Center()
+		east = east || c.Lon() > 0
+		west = west || c.Lon() < 0
+		if c.Lon() > -130 && c.Lon() < 130 {
+			t.Fatalf("%q at %v is outside face 3", k, c)
+		}
+	}
+	if !east || !west {
+		t.Fatalf("expected tiles on both sides of the antimeridian, got %v", keys)
+	}
+
+	// Level 6 cells on face 3 end at the antimeridian; tiles across it only
+	// touch them.
+	id := s2.CellIDFromLatLng(s2.LatLngFromDegrees(10, 179.9)).Parent(6)
+	keys, _ = KeysCoveringS2Cell(id, 8)
+	for _, k := range keys {
+		if k.Center().Lon() < 0 {
+			t.Fatalf("%q only touches the cell", k)
+		}
+	}
+}
+
+func TestS2CellsCoveringKey(t *testing.T) {
+	key := quadkey.FromLonLat(139.767125, 35.681236, 12)
+	ids, err := S2CellsCoveringKey(key, 13)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) == 0 || !slices.IsSorted(ids) {
+		t.Fatalf("expected sorted cells, got %v", ids)
+	}
+	corners := key.Corners()
+	for _, p := range append(corners[:], key.Center()) {
+		// Corners sit on tile edges; nudge them inside the tile.
+		c := key.Center()
+		p = orb.Point{p[0] + (c[0]-p[0])*1e-6, p[1] + (c[1]-p[1])*1e-6}
+		id := s2.CellIDFromLatLng(s2.LatLngFromDegrees(p.Lat(), p.Lon())).Parent(13)
+		if !slices.Contains(ids, id) {
+			t.Fatalf("point %v (%v) is not covered", p, id)
+		}
+	}
+	for _, id := range ids {
+		if id.Level() != 13 {
+			t.Fatalf("%v is at level %d, want 13", id, id.Level())
+		}
+	}
+}
+
+func TestS2CellsCoveringDeepPolarKey(t *testing.T) {
+	// Near the poles a zoom 32 tile is shorter than two edge margins.
+	key := quadkey.FromPoint(orb.Point{10, 85}, quadkey.MaxZoom)
+	ids, err := S2CellsCoveringKey(key, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := key.Center()
+	if want := s2.CellIDFromLatLng(s2.LatLngFromDegrees(c.Lat(), c.Lon())).Parent(30); !slices.Contains(ids, want) {
+		t.Fatalf("cell %v at the tile center is not covered, got %v", want, ids)
+	}
+
+	cell := s2.CellIDFromLatLng(s2.LatLngFromDegrees(c.Lat(), c.Lon()))
+	keys, err := KeysCoveringS2Cell(cell, quadkey.MaxZoom)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(keys, key) {
+		t.Fatalf("key %q is not covered by its level 30 cell, got %v", key, keys)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	if _, err := KeysCoveringS2Cell(s2.CellID(0), 10); err == nil {
+		t.Fatalf("expected error for an invalid cell")
+	}
+	id := s2.CellIDFromLatLng(s2.LatLngFromDegrees(0, 0)).Parent(10)
+	if _, err := KeysCoveringS2Cell(id, 33); err == nil {
+		t.Fatalf("expected error for zoom 33")
+	}
+	if _, err := S2CellsCoveringKey("01a", 5); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if _, err := S2CellsCoveringKey("0123", 31); err == nil {
+		t.Fatalf("expected error for level 31")
+	}
+}
@@ -0,0 +1,213 @@
+package quadkey
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// quadTreeNodeCapacity is the number of entries a node holds before it
+// splits into children, and the subtree size at or below which it merges
+// them back.
+const quadTreeNodeCapacity = 16
+
+// --------------------------
+// struct QuadTree
+// --------------------------
+
+// QuadTree is an in-memory spatial index of values stored under keys. Each
+// node stands for a tile and holds entries until it overflows, then pushes
+// the ones under finer keys down into its four children; removing entries
+// merges sparse subtrees back. Any number of values may share a key, and
+// keys of any zoom can be mixed.
+//
+// A QuadTree is not safe for concurrent use while it is being modified.
+type QuadTree[T any] struct {
+	root     quadTreeNode[T]
+	maxDepth int
+}
+
+type quadTreeEntry[T any] struct {
+	key   QuadKey
+	value T
+}
+
+type quadTreeNode[T any] struct {
+	key      QuadKey // "" for the root, which covers the world
+	entries  []quadTreeEntry[T]
+	children *[4]*quadTreeNode[T] // nil until the node splits
+	size     int                  // entries in the subtree
+}
+
+// NewQuadTree returns an empty tree whose InsertPoint files points under
+// keys at maxDepth, clamped to [1, MaxZoom].
+func NewQuadTree[T any](maxDepth int) *QuadTree[T] {
+	return &QuadTree[T]{maxDepth: max(1, min(maxDepth, MaxZoom))}
+}
+
+// Len returns the number of values in the tree.
+func (t *QuadTree[T]) Len() int {
+	return t.root.size
+}
+
+// Insert stores v under the key. An error is returned if the key is invalid.
+func (t *QuadTree[T]) Insert(key QuadKey, v T) error {
+	if err := key.Valid(); err != nil {
+		return err
+	}
+	t.root.insert(quadTreeEntry[T]{key, v})
+	return nil
+}
+
+// InsertPoint stores v under the key of the tile holding p at the tree's
+// maximum depth, and returns that key.
+func (t *QuadTree[T]) InsertPoint(p orb.Point, v T) QuadKey {
+	key := FromPoint(p, t.maxDepth)
+	t.root.insert(quadTreeEntry[T]{key, v})
+	return key
+}
+
+// Remove deletes every value stored under exactly the key, not under its
+// descendants, and returns how many were removed.
+func (t *QuadTree[T]) Remove(key QuadKey) int {
+	return t.root.remove(key)
+}
+
+// All yields every key and value in the tree, in no particular order.
+func (t *QuadTree[T]) All() iter.Seq2[QuadKey, T] {
+	return t.query(func(QuadKey) bool { return true })
+}
+
+// QueryBound yields the values whose tiles intersect bound, as
+// QuadKey.IntersectsBound decides, in no particular order.
+func (t *QuadTree[T]) QueryBound(bound orb.Bound) iter.Seq2[QuadKey, T] {
+	return t.query(func(key QuadKey) bool { return key.IntersectsBound(bound) })
+}
+
+// QueryPoint yields the values whose tiles contain p, as
+// QuadKey.ContainsPoint decides, in no particular order.
+func (t *QuadTree[T]) QueryPoint(p orb.Point) iter.Seq2[QuadKey, T] {
+	return t.query(func(key QuadKey) bool { return key.ContainsPoint(p) })
+}
+
+// QueryPrefix yields the values stored under the prefix key or any of its
+// descendants, in no particular order. An empty prefix yields every value.
+func (t *QuadTree[T]) QueryPrefix(prefix QuadKey) iter.Seq2[QuadKey, T] {
+	return t.query(func(key QuadKey) bool {
+		return strings.HasPrefix(string(key), string(prefix)) || strings.HasPrefix(string(prefix), string(key))
+	})
+}
+
+// query yields the entries whose keys match. match must also hold for every
+// ancestor of a matching key, so subtrees whose tile fails it are skipped.
+func (t *QuadTree[T]) query(match func(QuadKey) bool) iter.Seq2[QuadKey, T] {
+	return func(yield func(QuadKey, T) bool) {
+		t.root.walk(match, yield)
+	}
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+func (n *quadTreeNode[T]) insert(e quadTreeEntry[T]) {
+	n.size++
+	if n.children != nil && len(e.key) > len(n.key) {
+		n.child(e.key[len(n.key)] - '0').insert(e)
+		return
+	}
+	n.entries = append(n.entries, e)
+	if n.children == nil && len(n.entries) > quadTreeNodeCapacity {
+		n.split()
+	}
+}
+
+// child returns the child under digit d, creating it if needed.
+func (n *quadTreeNode[T]) child(d byte) *quadTreeNode[T] {
+	if n.children[d] == nil {
+		n.children[d] = &quadTreeNode[T]{key: n.key + QuadKey('0'+d)}
+	}
+	return n.children[d]
+}
+
+// split pushes the entries under finer keys down into children. Entries at
+// the node's own zoom stay.
+func (n *quadTreeNode[T]) split() {
+	n.children = new([4]*quadTreeNode[T])
+	entries := n.entries
+	n.entries = nil
+	for _, e := range entries {
+		if len(e.key) > len(n.key) {
+			n.child(e.key[len(n.key)] - '0').insert(e)
+		} else {
+			n.entries = append(n.entries, e)
+		}
+	}
+}
+
+func (n *quadTreeNode[T]) remove(key QuadKey) int {
+	removed := 0
+	if n.children != nil && len(key) > len(n.key) {
+		d := key[len(n.key)] - '0'
+		c := n.children[d]
+		if c == nil {
+			return 0
+		}
+		removed = c.remove(key)
+		if c.size == 0 {
+			n.children[d] = nil
+		}
+	} else {
+		kept := n.entries[:0]
+		for _, e := range n.entries {
+			if e.key == key {
+				removed++
+			} else {
+				kept = append(kept, e)
+			}
+		}
+		clear(n.entries[len(kept):])
+		n.entries = kept
+	}
+
+	n.size -= removed
+	if removed > 0 && n.children != nil && n.size <= quadTreeNodeCapacity {
+		n.merge()
+	}
+	return removed
+}
+
+// merge pulls every entry of the subtree back into the node.
+func (n *quadTreeNode[T]) merge() {
+	for _, c := range n.children {
+		if c != nil {
+			c.walk(func(QuadKey) bool { return true }, func(key QuadKey, v T) bool {
+				n.entries = append(n.entries, quadTreeEntry[T]{key, v})
+				return true
+			})
+		}
+	}
+	n.children = nil
+}
+
+// walk yields the subtree's entries whose keys match, and reports whether
+// the walk should continue.
+func (n *quadTreeNode[T]) walk(match func(QuadKey) bool, yield func(QuadKey, T) bool) bool {
+	if n.key != "" && !match(n.key) {
+		return true
+	}
+	for _, e := range n.entries {
+		if match(e.key) && !yield(e.key, e.value) {
+			return false
+		}
+	}
+	if n.children != nil {
+		for _, c := range n.children {
+			if c != nil && !c.walk(match, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
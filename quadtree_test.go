@@ -0,0 +1,127 @@
+package quadkey
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// collect gathers the values of seq by key.
+func collect[T any](seq func(func(QuadKey, T) bool)) map[QuadKey][]T {
+	out := map[QuadKey][]T{}
+	for key, v := range seq {
+		out[key] = append(out[key], v)
+	}
+	return out
+}
+
+func TestQuadTree(t *testing.T) {
+	tree := NewQuadTree[int](12)
+	rng := rand.New(rand.NewSource(1))
+	points := make([]orb.Point, 2000)
+	keys := make([]QuadKey, len(points))
+	for i := range points {
+		points[i] = orb.Point{rng.Float64()*40 - 20, rng.Float64()*40 - 20}
+		keys[i] = tree.InsertPoint(points[i], i)
+	}
+	if err := tree.Insert("1", -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tree.Insert("01a", -2); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+	if tree.Len() != len(points)+1 {
+		t.Fatalf("got %d values, want %d", tree.Len(), len(points)+1)
+	}
+	if tree.root.children == nil {
+		t.Fatalf("expected the root to have split")
+	}
+
+	// Every query matches a linear scan.
+	bound := orb.Bound{Min: orb.Point{-5, -3}, Max: orb.Point{7, 4}}
+	want := map[QuadKey][]int{"1": {-1}}
+	for i, key := range keys {
+		if key.IntersectsBound(bound) {
+			want[key] = append(want[key], i)
+		}
+	}
+	got := collect[int](tree.QueryBound(bound))
+	for _, vs := range got {
+		slices.Sort(vs)
+	}
+	if !maps.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("QueryBound: got %d keys, want %d", len(got), len(want))
+	}
+
+	p := points[7]
+	got = collect[int](tree.QueryPoint(p))
+	if !slices.Contains(got[keys[7]], 7) {
+		t.Fatalf("QueryPoint: %v lacks value 7", got)
+	}
+	for key := range got {
+		if !key.ContainsPoint(p) {
+			t.Fatalf("QueryPoint: %q does not contain %v", key, p)
+		}
+	}
+
+	prefix := keys[3][:6]
+	count := 0
+	for key := range tree.QueryPrefix(prefix) {
+		if !strings.HasPrefix(string(key), string(prefix)) {
+			t.Fatalf("QueryPrefix: %q is not under %q", key, prefix)
+		}
+		count++
+	}
+	wantCount := 0
+	for _, key := range keys {
+		if strings.HasPrefix(string(key), string(prefix)) {
+			wantCount++
+		}
+	}
+	if count != wantCount {
+		t.Fatalf("QueryPrefix: got %d values, want %d", count, wantCount)
+	}
+
+	// Stopping early is honored.
+	n := 0
+	for range tree.All() {
+		if n++; n == 10 {
+			break
+		}
+	}
+
+	// Removing everything merges the tree back into an empty root.
+	if got := tree.Remove("1"); got != 1 {
+		t.Fatalf("Remove(1): got %d, want 1", got)
+	}
+	if got := tree.Remove("0123"); got != 0 {
+		t.Fatalf("Remove of an absent key: got %d", got)
+	}
+	for _, key := range keys {
+		tree.Remove(key)
+	}
+	if tree.Len() != 0 || tree.root.children != nil || len(tree.root.entries) != 0 {
+		t.Fatalf("expected an empty, merged tree, got %d values", tree.Len())
+	}
+}
+
+func TestQuadTreeMerge(t *testing.T) {
+	tree := NewQuadTree[string](30)
+	for i := range quadTreeNodeCapacity + 1 {
+		tree.Insert(FromXYZ(i, 0, 5), "v")
+	}
+	if tree.root.children == nil {
+		t.Fatalf("expected a split after %d entries", quadTreeNodeCapacity+1)
+	}
+	tree.Remove(FromXYZ(0, 0, 5))
+	if tree.root.children != nil || len(tree.root.entries) != quadTreeNodeCapacity {
+		t.Fatalf("expected a merge back to %d root entries, got %d", quadTreeNodeCapacity, len(tree.root.entries))
+	}
+	if got := len(collect[string](tree.All())); got != quadTreeNodeCapacity {
+		t.Fatalf("got %d keys after the merge", got)
+	}
+}
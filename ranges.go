@@ -0,0 +1,84 @@
+package quadkey
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// --------------------------
+// struct KeyRange
+// --------------------------
+
+// KeyRange is an inclusive range of Z-order values at a fixed zoom. The
+// Z-order value of a key is its digits read as a base-4 number, so the
+// descendants of any key at a deeper zoom form one contiguous range, and a
+// range maps to a single BETWEEN predicate on an integer column.
+type KeyRange struct {
+	Start, End uint64
+}
+
+// ZRange returns the range of Z-order values of the key's descendants at
+// zoom. An error is returned if the key is invalid or finer than zoom, or if
+// zoom exceeds MaxZoom, the deepest zoom whose values fit in 64 bits.
+func (key QuadKey) ZRange(zoom int) (KeyRange, error) {
+	if err := key.Valid(); err != nil {
+		return KeyRange{}, err
+	}
+	if zoom > MaxZoom {
+		return KeyRange{}, fmt.Errorf("zoom %d is out of range [1, %d]", zoom, MaxZoom)
+	}
+	if key.Z() > zoom {
+		return KeyRange{}, fmt.Errorf("key %q is finer than zoom %d", key, zoom)
+	}
+
+	v := key.Morton()
+	shift := 2 * uint(zoom-key.Z())
+	// For the last tiles of zoom 32 (v+1)<<shift wraps to 0, leaving End at
+	// the largest uint64 as intended.
+	return KeyRange{Start: v << shift, End: (v+1)<<shift - 1}, nil
+}
+
+// PrefixRange returns the smallest and largest strings among the key and its
+// descendants down to maxZoom, for BETWEEN queries on string-keyed stores.
+// lo is the key itself, which sorts before all of its descendants, and hi is
+// the key padded with '3' digits to maxZoom. Every descendant at any zoom up
+// to maxZoom lies within [lo, hi] and no other key does, so the range works
+// for stores holding mixed zooms as well as for fixed-zoom ones. Invalid
+// keys and zooms coarser than the key return empty strings.
+func (key QuadKey) PrefixRange(maxZoom int) (lo, hi QuadKey) {
+	if key.Valid() != nil || maxZoom < key.Z() {
+		return "", ""
+	}
+	return key, key + QuadKey(strings.Repeat("3", maxZoom-key.Z()))
+}
+
+// KeyRanges returns the Z-order ranges at zoom covering the keys, sorted and
+// with overlapping or adjacent ranges merged, so a whole coverage turns into
+// a few range predicates. Keys may mix zooms. An error is returned as for
+// ZRange.
+func KeyRanges(keys []QuadKey, zoom int) ([]KeyRange, error) {
+	ranges := make([]KeyRange, 0, len(keys))
+	for _, key := range keys {
+		r, err := key.ZRange(zoom)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	slices.SortFunc(ranges, func(a, b KeyRange) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+
+	merged := make([]KeyRange, 0, len(ranges))
+	for _, r := range ranges {
+		// Overlapping or adjacent; End+1 is avoided as it wraps at the top.
+		if n := len(merged); n > 0 && (r.Start <= merged[n-1].End || r.Start-1 == merged[n-1].End) {
+			merged[n-1].End = max(merged[n-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
@@ -0,0 +1,124 @@
+package quadkey
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestZRange(t *testing.T) {
+	tests := []struct {
+		key  QuadKey
+		zoom int
+		want KeyRange
+	}{
+		{"0", 1, KeyRange{0, 0}},
+		{"3", 1, KeyRange{3, 3}},
+		{"1", 2, KeyRange{4, 7}},
+		{"21", 3, KeyRange{36, 39}},
+		{"0123", 4, KeyRange{27, 27}},
+		{"3", 32, KeyRange{3 << 62, math.MaxUint64}},
+		{"0", 32, KeyRange{0, 1<<62 - 1}},
+	}
+	for _, tt := range tests {
+		got, err := tt.key.ZRange(tt.zoom)
+		if err != nil {
+			t.Fatalf("%q at %d: unexpected error: %v", tt.key, tt.zoom, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q at %d: got %v, want %v", tt.key, tt.zoom, got, tt.want)
+		}
+	}
+
+	// Every descendant's own value lies within its ancestor's range.
+	r, _ := QuadKey("1203").ZRange(8)
+	for d := range QuadKey("1203").DescendantsAtZoom(8) {
+		v, _ := d.ZRange(8)
+		if v.Start != v.End || v.Start < r.Start || v.End > r.End {
+			t.Fatalf("%q: value %v outside %v", d, v, r)
+		}
+	}
+
+	for _, tt := range []struct {
+		key  QuadKey
+		zoom int
+	}{{"012", 2}, {"01a", 5}, {"0", 33}} {
+		if _, err := tt.key.ZRange(tt.zoom); err == nil {
+			t.Fatalf("%q at %d: expected error", tt.key, tt.zoom)
+		}
+	}
+}
+
+func TestKeyRanges(t *testing.T) {
+	got, err := KeyRanges([]QuadKey{"2", "10", "11", "13", "0", "01", "33"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []KeyRange{{0, 5}, {7, 11}, {15, 15}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	top, err := KeyRanges([]QuadKey{"3", "2"}, 32)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []KeyRange{{2 << 62, math.MaxUint64}}; !slices.Equal(top, want) {
+		t.Fatalf("top: got %v, want %v", top, want)
+	}
+
+	if _, err := KeyRanges([]QuadKey{"0", "0123"}, 3); err == nil {
+		t.Fatalf("expected error for a key finer than the zoom")
+	}
+}
+
+func TestKeyRangesCompactCoverage(t *testing.T) {
+	poly := orb.Polygon{{{139.0, 35.0}, {140.5, 35.2}, {140.0, 36.5}, {139.2, 36.0}, {139.0, 35.0}}}
+	keys := Cover(poly, 12)
+	ranges, err := KeyRanges(keys, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ranges) >= len(keys) {
+		t.Fatalf("expected fewer ranges than keys, got %d for %d", len(ranges), len(keys))
+	}
+
+	total := uint64(0)
+	for _, r := range ranges {
+		total += r.End - r.Start + 1
+	}
+	if total != uint64(len(keys)) {
+		t.Fatalf("ranges span %d values, want %d", total, len(keys))
+	}
+}
+
+func TestPrefixRange(t *testing.T) {
+	lo, hi := QuadKey("0123").PrefixRange(7)
+	if lo != "0123" || hi != "0123333" {
+		t.Fatalf("got [%q, %q]", lo, hi)
+	}
+
+	// Exactly the key and its descendants fall in the range.
+	for _, k := range []QuadKey{"0123", "01230", "0123000", "0123303", "0123333"} {
+		if k < lo || k > hi {
+			t.Fatalf("descendant %q outside [%q, %q]", k, lo, hi)
+		}
+	}
+	for _, k := range []QuadKey{"012", "0122333", "0130", "01"} {
+		if k >= lo && k <= hi {
+			t.Fatalf("non-descendant %q inside [%q, %q]", k, lo, hi)
+		}
+	}
+
+	if lo, hi := QuadKey("0123").PrefixRange(4); lo != "0123" || hi != "0123" {
+		t.Fatalf("same zoom: got [%q, %q]", lo, hi)
+	}
+	if lo, hi := QuadKey("0123").PrefixRange(3); lo != "" || hi != "" {
+		t.Fatalf("coarser zoom: got [%q, %q]", lo, hi)
+	}
+	if lo, hi := QuadKey("01a").PrefixRange(5); lo != "" || hi != "" {
+		t.Fatalf("invalid key: got [%q, %q]", lo, hi)
+	}
+}
@@ -0,0 +1,221 @@
+package quadkey
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"image"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// SVG rendering
+// --------------------------
+
+// SVGStyle is the paint of the tiles of one zoom in ToSVG output. Colors are
+// any SVG color, such as "#3388ff" or "red"; an empty color paints nothing.
+type SVGStyle struct {
+	Fill        string
+	FillOpacity float64 // 0 leaves the fill opaque
+	Stroke      string
+	StrokeWidth float64 // in output pixels
+}
+
+// defaultSVGStyle paints tiles when SVGOptions.Style is nil.
+var defaultSVGStyle = SVGStyle{Fill: "#3388ff", FillOpacity: 0.25, Stroke: "#3388ff", StrokeWidth: 1}
+
+// SVGOptions tunes the drawing made by ToSVG.
+type SVGOptions struct {
+	// Width is the width of the drawing in pixels, 512 if not positive. The
+	// height follows from the extent of the keys.
+	Width int
+	// Style, if set, returns the paint of the tiles at each zoom.
+	Style func(zoom int) SVGStyle
+}
+
+// ToSVG draws the keys in Web Mercator as a standalone SVG document framed
+// on their extent, for coverage diagrams in reports and docs. Coarser tiles
+// are drawn first, so finer ones stay visible on top. Invalid keys are
+// skipped; with none left the drawing is a blank square.
+func ToSVG(opts SVGOptions, keys ...QuadKey) []byte {
+	width := opts.Width
+	if width <= 0 {
+		width = 512
+	}
+	style := opts.Style
+	if style == nil {
+		style = func(int) SVGStyle { return defaultSVGStyle }
+	}
+
+	valid := make([]QuadKey, 0, len(keys))
+	for _, key := range keys {
+		if key.Valid() == nil {
+			valid = append(valid, key)
+		}
+	}
+	slices.SortStableFunc(valid, func(a, b QuadKey) int { return cmp.Compare(a.Z(), b.Z()) })
+
+	// Frame the drawing on the keys' extent in world units, where the whole
+	// Web Mercator square is [0, 1] on both axes.
+	minU, minV, maxU, maxV := 0.0, 0.0, 1.0, 1.0
+	for i, key := range valid {
+		u0, v0, u1, v1 := worldRect(key)
+		if i == 0 {
+			minU, minV, maxU, maxV = u0, v0, u1, v1
+			continue
+		}
+		minU, minV = math.Min(minU, u0), math.Min(minV, v0)
+		maxU, maxV = math.Max(maxU, u1), math.Max(maxV, v1)
+	}
+	scale := float64(width) / (maxU - minU)
+	height := max(int(math.Round((maxV-minV)*scale)), 1)
+
+	var b bytes.Buffer
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + strconv.Itoa(width) +
+		`" height="` + strconv.Itoa(height) + `" viewBox="0 0 ` + strconv.Itoa(width) + " " + strconv.Itoa(height) + `">` + "\n")
+	for _, key := range valid {
+		u0, v0, u1, v1 := worldRect(key)
+		s := style(key.Z())
+		b.WriteString(`<rect x="` + svgNumber((u0-minU)*scale) + `" y="` + svgNumber((v0-minV)*scale) +
+			`" width="` + svgNumber((u1-u0)*scale) + `" height="` + svgNumber((v1-v0)*scale) + `"`)
+		writeSVGAttr(&b, "fill", cmp.Or(s.Fill, "none"))
+		if s.Fill != "" && s.FillOpacity > 0 {
+			writeSVGAttr(&b, "fill-opacity", svgNumber(s.FillOpacity))
+		}
+		if s.Stroke != "" && s.StrokeWidth > 0 {
+			writeSVGAttr(&b, "stroke", s.Stroke)
+			writeSVGAttr(&b, "stroke-width", svgNumber(s.StrokeWidth))
+		}
+		b.WriteString("><title>" + string(key) + "</title></rect>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// --------------------------
+// raster masks
+// --------------------------
+
+// RasterizeMask draws the keys onto a width by height mask of the bound,
+// spaced evenly in Web Mercator like a map image: a pixel is 255 if its
+// center lies in one of the tiles and 0 otherwise. Masks of two coverages
+// can be diffed pixel by pixel or fed to raster pipelines. Invalid keys are
+// skipped and latitudes are clamped to the Web Mercator range.
+func RasterizeMask(keys []QuadKey, bound orb.Bound, width, height int) *image.Gray {
+	mask := image.NewGray(image.Rect(0, 0, max(width, 0), max(height, 0)))
+	if width <= 0 || height <= 0 {
+		return mask
+	}
+
+	clampLat := func(lat float64) float64 { return math.Max(-MERCATOR_MAX_LAT, math.Min(lat, MERCATOR_MAX_LAT)) }
+	minU, maxU := fracX(bound.Left(), 1), fracX(bound.Right(), 1)
+	minV, maxV := fracY(clampLat(bound.Top()), 1), fracY(clampLat(bound.Bottom()), 1)
+	du, dv := (maxU-minU)/float64(width), (maxV-minV)/float64(height)
+	if !(du > 0) || !(dv > 0) {
+		return mask
+	}
+
+	for _, key := range keys {
+		if key.Valid() != nil {
+			continue
+		}
+		u0, v0, u1, v1 := worldRect(key)
+		// Pixel i is covered when its center, at (i+0.5) steps, lies in
+		// [u0, u1).
+		x0, x1 := maskSpan(u0, u1, minU, du, width)
+		y0, y1 := maskSpan(v0, v1, minV, dv, height)
+		for y := y0; y < y1; y++ {
+			row := mask.Pix[y*mask.Stride:]
+			for x := x0; x < x1; x++ {
+				row[x] = 255
+			}
+		}
+	}
+	return mask
+}
+
+// --------------------------
+// ASCII grids
+// --------------------------
+
+// ASCIIGrid draws the tiles KeysInBound returns for the bound at zoom as text,
+// one line per tile row from north to south, for debugging coverages in a
+// terminal. A tile is '#' if it or an ancestor is in keys, '+' if only finer
+// keys inside it are, and '.' otherwise. A header line gives the zoom and
+// tile ranges. Each tile takes one character, so keep the range small. Zooms
+// outside [1, MaxZoom] return "".
+func ASCIIGrid(bound orb.Bound, zoom int, keys []QuadKey) string {
+	if zoom < 1 || zoom > MaxZoom {
+		return ""
+	}
+	minX, minY, maxX, maxY := tileRange(bound, zoom)
+	full := make(map[QuadKey]bool, len(keys))
+	partial := make(map[QuadKey]bool)
+	for _, key := range keys {
+		if key.Valid() != nil {
+			continue
+		}
+		full[key] = true
+		if key.Z() > zoom {
+			partial[key[:zoom]] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("z=" + strconv.Itoa(zoom) + " x=" + strconv.Itoa(minX) + ".." + strconv.Itoa(maxX) +
+		" y=" + strconv.Itoa(minY) + ".." + strconv.Itoa(maxY) + "\n")
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			key := FromXYZ(x, y, zoom)
+			c := byte('.')
+			if partial[key] {
+				c = '+'
+			}
+			for z := 1; z <= zoom; z++ {
+				if full[key[:z]] {
+					c = '#'
+					break
+				}
+			}
+			b.WriteByte(c)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// maskSpan returns the range [first, last) of the n pixels, starting at lo in
+// steps of d, whose centers lie in [a, b).
+func maskSpan(a, b, lo, d float64, n int) (first, last int) {
+	first = int(math.Max(0, math.Ceil((a-lo)/d-0.5)))
+	last = int(math.Min(float64(n), math.Ceil((b-lo)/d-0.5)))
+	return first, max(first, last)
+}
+
+// worldRect returns the tile's extent in world units, with v growing south.
+func worldRect(key QuadKey) (u0, v0, u1, v1 float64) {
+	x, y, z := key.tileXY()
+	n := math.Exp2(float64(z))
+	return float64(x) / n, float64(y) / n, float64(x+1) / n, float64(y+1) / n
+}
+
+// svgNumber formats v to two decimal places, trimming trailing zeros.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// writeSVGAttr appends name="value" with the value escaped.
+func writeSVGAttr(b *bytes.Buffer, name, value string) {
+	b.WriteString(" " + name + `="`)
+	xml.EscapeText(b, []byte(value))
+	b.WriteByte('"')
+}
@@ -0,0 +1,120 @@
+package quadkey
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestToSVG(t *testing.T) {
+	var doc struct {
+		Width  int `xml:"width,attr"`
+		Height int `xml:"height,attr"`
+		Rects  []struct {
+			X      float64 `xml:"x,attr"`
+			Y      float64 `xml:"y,attr"`
+			Width  float64 `xml:"width,attr"`
+			Fill   string  `xml:"fill,attr"`
+			Stroke string  `xml:"stroke,attr"`
+			Title  string  `xml:"title"`
+		} `xml:"rect"`
+	}
+
+	style := func(zoom int) SVGStyle {
+		if zoom == 1 {
+			return SVGStyle{Fill: "#eee", Stroke: "<black>", StrokeWidth: 2}
+		}
+		return SVGStyle{Stroke: "red", StrokeWidth: 1}
+	}
+	data := ToSVG(SVGOptions{Width: 200, Style: style}, "01", "0", "01a", "1")
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SVG: %v\n%s", err, data)
+	}
+
+	// Keys "0" and "1" span the north half of the world: twice as wide as
+	// tall.
+	if doc.Width != 200 || doc.Height != 100 {
+		t.Fatalf("got size %dx%d, want 200x100", doc.Width, doc.Height)
+	}
+	if len(doc.Rects) != 3 {
+		t.Fatalf("got %d rects, want 3", len(doc.Rects))
+	}
+	// Coarser tiles first, input order kept within a zoom.
+	for i, want := range []string{"0", "1", "01"} {
+		if doc.Rects[i].Title != want {
+			t.Fatalf("rect %d: got %q, want %q", i, doc.Rects[i].Title, want)
+		}
+	}
+	r := doc.Rects[2]
+	if r.X != 50 || r.Y != 0 || r.Width != 50 || r.Fill != "none" || r.Stroke != "red" {
+		t.Fatalf("unexpected rect for 01: %+v", r)
+	}
+	if doc.Rects[0].Fill != "#eee" || doc.Rects[0].Stroke != "<black>" {
+		t.Fatalf("unexpected style for zoom 1: %+v", doc.Rects[0])
+	}
+
+	doc.Rects = nil
+	if err := xml.Unmarshal(ToSVG(SVGOptions{}), &doc); err != nil || doc.Width != 512 || doc.Height != 512 || len(doc.Rects) != 0 {
+		t.Fatalf("empty drawing: %dx%d with %d rects, err %v", doc.Width, doc.Height, len(doc.Rects), err)
+	}
+}
+
+func TestRasterizeMask(t *testing.T) {
+	world := orb.Bound{Min: orb.Point{-180, -MERCATOR_MAX_LAT}, Max: orb.Point{180, MERCATOR_MAX_LAT}}
+	// Key "1" is the north-east quarter; "21" a sixteenth west of it.
+	mask := RasterizeMask([]QuadKey{"1", "21", "01a"}, world, 8, 8)
+	want := []string{
+		"....####",
+		"....####",
+		"....####",
+		"....####",
+		"..##....",
+		"..##....",
+		"........",
+		"........",
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := mask.GrayAt(x, y).Y; (got == 255) != (c == '#') {
+				t.Fatalf("pixel (%d, %d): got %d, want %c", x, y, got, c)
+			}
+		}
+	}
+
+	// A bound inside one tile is covered by it entirely.
+	inner := QuadKey("0231").Bound()
+	inner.Min[0] += 1
+	if m := RasterizeMask([]QuadKey{"023"}, inner, 4, 3); m.Bounds().Dx() != 4 || m.Bounds().Dy() != 3 {
+		t.Fatalf("got mask size %v, want 4x3", m.Bounds())
+	} else {
+		for _, v := range m.Pix {
+			if v != 255 {
+				t.Fatalf("expected a full mask, got %v", m.Pix)
+			}
+		}
+	}
+
+	if m := RasterizeMask([]QuadKey{"0"}, world, 0, 5); len(m.Pix) != 0 {
+		t.Fatalf("expected an empty mask")
+	}
+}
+
+func TestASCIIGrid(t *testing.T) {
+	world := orb.Bound{Min: orb.Point{-179.9, -85}, Max: orb.Point{179.9, 85}}
+	got := ASCIIGrid(world, 2, []QuadKey{"1", "210", "33", "01a"})
+	want := "z=2 x=0..3 y=0..3\n" +
+		"..##\n" +
+		"..##\n" +
+		".+..\n" +
+		"...#\n"
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	for _, zoom := range []int{0, -1, MaxZoom + 1} {
+		if got := ASCIIGrid(world, zoom, []QuadKey{"1", "210"}); got != "" {
+			t.Fatalf("zoom %d: got %q, want an empty grid", zoom, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package quadkey
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/paulmach/orb"
+)
+
+// --------------------------
+// seeding plans
+// --------------------------
+
+// SeedOptions tunes the plan built by PlanSeed.
+type SeedOptions struct {
+	// MinZoom and MaxZoom are the range of zooms to seed, inclusive.
+	MinZoom, MaxZoom int
+	// BatchSize is the number of keys per batch, 1000 if not positive.
+	BatchSize int
+	// MaxTiles, if positive, caps the tiles of the whole plan.
+	MaxTiles int
+	// TileBytes is the expected average size of one tile, from which batch
+	// and plan byte estimates are derived. 0 leaves the estimates 0.
+	TileBytes int64
+	// Workers is passed to CoverWithOptions to cover each zoom concurrently.
+	Workers int
+}
+
+// SeedBatch is one unit of work of a seeding plan: keys of a single zoom.
+type SeedBatch struct {
+	Zoom  int
+	Keys  []QuadKey
+	Bytes int64 // estimated size of the batch's tiles
+}
+
+// SeedPlan is an ordered list of batches covering a geometry over a range of
+// zooms, with totals for sizing the job.
+type SeedPlan struct {
+	Batches []SeedBatch
+	Tiles   int64
+	Bytes   int64
+}
+
+// PlanSeed covers g at every zoom from opts.MinZoom to opts.MaxZoom and
+// splits the tiles into batches for a cache-warming job. Zooms are planned
+// from the coarsest, so overviews are warm first, and keys within a zoom
+// follow the Hilbert curve, so each batch is a compact patch of neighboring
+// tiles. An error is returned if the zoom range is invalid, or a
+// *TooManyTilesError if the plan exceeds opts.MaxTiles; covering stops soon
+// after the limit is passed, so its Count may be below the full plan's.
+func PlanSeed(g orb.Geometry, opts SeedOptions) (SeedPlan, error) {
+	if opts.MinZoom < 1 || opts.MaxZoom > MaxZoom || opts.MinZoom > opts.MaxZoom {
+		return SeedPlan{}, fmt.Errorf("zoom range [%d, %d] is not within [1, %d]", opts.MinZoom, opts.MaxZoom, MaxZoom)
+	}
+	size := opts.BatchSize
+	if size <= 0 {
+		size = 1000
+	}
+
+	plan := SeedPlan{Batches: []SeedBatch{}}
+	for z := opts.MinZoom; z <= opts.MaxZoom; z++ {
+		limit := int64(-1)
+		if opts.MaxTiles > 0 {
+			limit = int64(opts.MaxTiles) - plan.Tiles
+		}
+		keys, found := coverWithin(g, z, opts.Workers, limit)
+		plan.Tiles += found
+		if opts.MaxTiles > 0 && plan.Tiles > int64(opts.MaxTiles) {
+			return SeedPlan{}, &TooManyTilesError{Count: plan.Tiles, Limit: opts.MaxTiles}
+		}
+
+		slices.SortFunc(keys, func(a, b QuadKey) int { return cmp.Compare(a.HilbertIndex(), b.HilbertIndex()) })
+		for chunk := range slices.Chunk(keys, size) {
+			plan.Batches = append(plan.Batches, SeedBatch{
+				Zoom:  z,
+				Keys:  chunk,
+				Bytes: int64(len(chunk)) * opts.TileBytes,
+			})
+		}
+	}
+	plan.Bytes = plan.Tiles * opts.TileBytes
+	return plan, nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// coverWithin covers g at zoom with the given workers, giving up once more
+// than limit tiles have been found, so an oversized level is never built
+// whole. It returns the keys and their count, or nil and a count above limit.
+// A negative limit covers without one.
+func coverWithin(g orb.Geometry, zoom, workers int, limit int64) ([]QuadKey, int64) {
+	opts := CoverOptions{Workers: workers}
+	if limit < 0 || g == nil || CountKeysInBound(g.Bound(), zoom) <= limit {
+		// The tiles of the bounding box are an upper bound for any geometry.
+		keys := CoverWithOptions(g, zoom, opts)
+		return keys, int64(len(keys))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var found int64
+	opts.Progress = func(emitted, _ int64) {
+		if found = emitted; emitted > limit {
+			cancel()
+		}
+	}
+	keys, err := CoverCtx(ctx, g, zoom, opts)
+	if err != nil || found > limit {
+		return nil, max(found, limit+1)
+	}
+	return keys, int64(len(keys))
+}
@@ -0,0 +1,61 @@
+package quadkey
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestPlanSeed(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{139, 35}, Max: orb.Point{141, 37}}
+	plan, err := PlanSeed(bound, SeedOptions{MinZoom: 6, MaxZoom: 9, BatchSize: 5, TileBytes: 100, Workers: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var tiles int64
+	prevZoom := 0
+	for i, batch := range plan.Batches {
+		if batch.Zoom < prevZoom {
+			t.Fatalf("batch %d: zoom %d after %d", i, batch.Zoom, prevZoom)
+		}
+		prevZoom = batch.Zoom
+		if len(batch.Keys) == 0 || len(batch.Keys) > 5 || batch.Bytes != int64(len(batch.Keys))*100 {
+			t.Fatalf("batch %d: %d keys, %d bytes", i, len(batch.Keys), batch.Bytes)
+		}
+		for j, key := range batch.Keys {
+			if key.Z() != batch.Zoom {
+				t.Fatalf("batch %d: key %q is not at zoom %d", i, key, batch.Zoom)
+			}
+			if j > 0 && batch.Keys[j-1].HilbertIndex() > key.HilbertIndex() {
+				t.Fatalf("batch %d: keys out of Hilbert order", i)
+			}
+		}
+		tiles += int64(len(batch.Keys))
+	}
+
+	var want int64
+	for z := 6; z <= 9; z++ {
+		want += int64(len(Cover(bound, z)))
+	}
+	if plan.Tiles != want || tiles != want || plan.Bytes != want*100 {
+		t.Fatalf("got %d tiles (%d in batches), %d bytes, want %d tiles", plan.Tiles, tiles, plan.Bytes, want)
+	}
+
+	if _, err := PlanSeed(bound, SeedOptions{MinZoom: 6, MaxZoom: 9, MaxTiles: int(want - 1)}); !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("expected ErrTooManyTiles, got %v", err)
+	}
+
+	// A level far over the limit must fail without being covered whole.
+	world := orb.Polygon{{{-180, -85}, {180, -85}, {180, 85}, {-180, 85}, {-180, -85}}}
+	var tooMany *TooManyTilesError
+	if _, err := PlanSeed(world, SeedOptions{MinZoom: 16, MaxZoom: 16, MaxTiles: 1000, Workers: 2}); !errors.As(err, &tooMany) || tooMany.Limit != 1000 || tooMany.Count <= 1000 {
+		t.Fatalf("expected a *TooManyTilesError over 1000, got %v", err)
+	}
+	for _, opts := range []SeedOptions{{MinZoom: 0, MaxZoom: 3}, {MinZoom: 5, MaxZoom: 4}, {MinZoom: 1, MaxZoom: MaxZoom + 1}} {
+		if _, err := PlanSeed(bound, opts); err == nil {
+			t.Fatalf("%+v: expected error", opts)
+		}
+	}
+}
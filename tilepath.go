@@ -0,0 +1,103 @@
+package quadkey
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --------------------------
+// tile paths
+// --------------------------
+
+// FromTilePath parses a slippy-map tile path such as "12/3639/1612",
+// "12/3639/1612.png" or "12/3639/1612@2x.webp", or a full tile URL whose
+// path ends in one, such as "https://tile.example.com/osm/12/3639/1612.png?key=k".
+// The last z/x/y triple in the path is used; a query string or fragment is
+// ignored. An error is returned if there is no triple, the zoom is out of
+// range, or the tile lies outside the grid.
+func FromTilePath(s string) (QuadKey, error) {
+	path := s
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if _, rest, ok := strings.Cut(path, "://"); ok {
+		// Drop the host; a URL without a path has no tile in it.
+		_, path, _ = strings.Cut(rest, "/")
+	}
+
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segs) - 3; i >= 0; i-- {
+		z, okZ := parseTileNumber(segs[i])
+		x, okX := parseTileNumber(segs[i+1])
+		y, okY := parseTileNumber(stripTileSuffix(segs[i+2]))
+		if !okZ || !okX || !okY {
+			continue
+		}
+		return FromXYZChecked(x, y, z)
+	}
+	return "", fmt.Errorf("no z/x/y tile path in %q", s)
+}
+
+// maxGeotileZoom is the deepest zoom of Elasticsearch's geotile_grid
+// aggregation.
+const maxGeotileZoom = 29
+
+// GeotileKey returns the "zoom/x/y" bucket key Elasticsearch's geotile_grid
+// aggregation uses for the key's tile, or "" if the key is invalid.
+func (key QuadKey) GeotileKey() string {
+	x, y, z := key.XYZ()
+	if z < 0 {
+		return ""
+	}
+	return strconv.Itoa(z) + "/" + strconv.Itoa(x) + "/" + strconv.Itoa(y)
+}
+
+// FromGeotileKey parses a geotile_grid bucket key. Unlike FromTilePath it
+// accepts nothing but the bare "zoom/x/y" form. An error is returned if the
+// key is malformed, its zoom is outside [1, 29] (zoom 0, the whole world,
+// has no quadkey), or the tile lies outside the grid.
+func FromGeotileKey(s string) (QuadKey, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("geotile key %q is not zoom/x/y", s)
+	}
+	var nums [3]int
+	for i, part := range parts {
+		n, ok := parseTileNumber(part)
+		if !ok {
+			return "", fmt.Errorf("geotile key %q is not zoom/x/y", s)
+		}
+		nums[i] = n
+	}
+	z, x, y := nums[0], nums[1], nums[2]
+	if z < 1 || z > maxGeotileZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, maxGeotileZoom)
+	}
+	if x >= 1<<z || y >= 1<<z {
+		return "", fmt.Errorf("tile (%d, %d) is outside zoom %d", x, y, z)
+	}
+	return FromXYZ(x, y, z), nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// parseTileNumber parses a path segment made only of decimal digits.
+func parseTileNumber(seg string) (int, bool) {
+	if seg == "" || len(seg) > 10 || strings.TrimLeft(seg, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(seg)
+	return n, err == nil
+}
+
+// stripTileSuffix removes a file extension and a retina scale suffix such
+// as "@2x" from the last segment of a tile path.
+func stripTileSuffix(seg string) string {
+	if i := strings.IndexAny(seg, ".@"); i >= 0 {
+		return seg[:i]
+	}
+	return seg
+}
@@ -0,0 +1,60 @@
+package quadkey
+
+import "testing"
+
+func TestFromTilePath(t *testing.T) {
+	want := FromXYZ(3639, 1612, 12)
+	for _, s := range []string{
+		"12/3639/1612",
+		"/12/3639/1612.png",
+		"12/3639/1612@2x.webp",
+		"12/3639/1612.pbf/",
+		"/tiles/osm/12/3639/1612.png",
+		"https://a.tile.example.com/12/3639/1612.png",
+		"https://tile.example.com/v1/osm/12/3639/1612.png?key=42/1/1#frag",
+		"//cdn.example.com/styles/256/12/3639/1612",
+	} {
+		got, err := FromTilePath(s)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %q, err %v, want %q", s, got, err, want)
+		}
+	}
+}
+
+func TestFromTilePathInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"12/3639",
+		"a/b/c.png",
+		"12/-1/5",
+		"12/+1/5",
+		"0/0/0.png",
+		"33/0/0",
+		"3/8/0",
+		"3/0/8.png",
+		"https://tile.example.com",
+		"https://tile.example.com/?z=1/0/0",
+	} {
+		if got, err := FromTilePath(s); err == nil {
+			t.Fatalf("%q: expected error, got %q", s, got)
+		}
+	}
+}
+
+func TestGeotileKey(t *testing.T) {
+	key := FromXYZ(3639, 1612, 12)
+	if got := key.GeotileKey(); got != "12/3639/1612" {
+		t.Fatalf("got %q, want %q", got, "12/3639/1612")
+	}
+	if got, err := FromGeotileKey("12/3639/1612"); err != nil || got != key {
+		t.Fatalf("round trip: got %q, err %v", got, err)
+	}
+	if got := QuadKey("01a").GeotileKey(); got != "" {
+		t.Fatalf("expected empty key for an invalid key, got %q", got)
+	}
+	for _, s := range []string{"", "0/0/0", "30/0/0", "12/3639/1612.png", "/12/3639/1612", "12/3639", "3/8/0", "3/0/-1"} {
+		if got, err := FromGeotileKey(s); err == nil {
+			t.Fatalf("%q: expected error, got %q", s, got)
+		}
+	}
+}
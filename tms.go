@@ -0,0 +1,40 @@
+package quadkey
+
+import "fmt"
+
+// --------------------------
+// TMS tile coordinates
+// --------------------------
+
+// TMS returns the key's tile coordinates in the TMS scheme, where row 0 is
+// the southernmost row rather than the northernmost as in XYZ. MBTiles
+// stores tiles this way. Invalid keys return -1, -1, -1.
+func (key QuadKey) TMS() (x, y, z int) {
+	x, y, z = key.XYZ()
+	if z < 0 {
+		return -1, -1, -1
+	}
+	return x, flipY(y, z), z
+}
+
+// FromTMS returns the key of TMS tile (x, y) at zoom z. An error is returned
+// if z is out of range or the tile lies outside the grid.
+func FromTMS(x, y, z int) (QuadKey, error) {
+	if z < 1 || z > MaxZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", z, MaxZoom)
+	}
+	if x < 0 || y < 0 || int64(x) >= 1<<z || int64(y) >= 1<<z {
+		return "", fmt.Errorf("tile (%d, %d) is outside zoom %d", x, y, z)
+	}
+	return FromXYZ(x, flipY(y, z), z), nil
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// flipY converts a row between the XYZ and TMS schemes; the flip is its own
+// inverse.
+func flipY(y, z int) int {
+	return 1<<z - 1 - y
+}
@@ -0,0 +1,42 @@
+package quadkey
+
+import "testing"
+
+func TestTMS(t *testing.T) {
+	tests := []struct {
+		key     QuadKey
+		x, y, z int
+	}{
+		{"0", 0, 1, 1},
+		{"2", 0, 0, 1},
+		{"3", 1, 0, 1},
+		{"13300221", 225, 153, 8},
+	}
+	for _, tt := range tests {
+		x, y, z := tt.key.TMS()
+		if x != tt.x || y != tt.y || z != tt.z {
+			t.Fatalf("%q: got (%d, %d, %d), want (%d, %d, %d)", tt.key, x, y, z, tt.x, tt.y, tt.z)
+		}
+		got, err := FromTMS(tt.x, tt.y, tt.z)
+		if err != nil || got != tt.key {
+			t.Fatalf("FromTMS(%d, %d, %d): got %q, err %v, want %q", tt.x, tt.y, tt.z, got, err, tt.key)
+		}
+	}
+
+	// TMS and XYZ rows mirror each other around the equator.
+	_, y, z := QuadKey("13300221").XYZ()
+	if _, ty, _ := QuadKey("13300221").TMS(); y+ty != 1<<z-1 {
+		t.Fatalf("XYZ row %d and TMS row %d do not mirror at zoom %d", y, ty, z)
+	}
+}
+
+func TestTMSInvalid(t *testing.T) {
+	if x, y, z := QuadKey("01a").TMS(); x != -1 || y != -1 || z != -1 {
+		t.Fatalf("expected (-1,-1,-1) for invalid key, got (%d,%d,%d)", x, y, z)
+	}
+	for _, c := range [][3]int{{0, 0, 0}, {0, 0, MaxZoom + 1}, {-1, 0, 3}, {0, 8, 3}, {8, 0, 3}} {
+		if _, err := FromTMS(c[0], c[1], c[2]); err == nil {
+			t.Fatalf("FromTMS%v: expected error", c)
+		}
+	}
+}
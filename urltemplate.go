@@ -0,0 +1,67 @@
+package quadkey
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultSubdomains are the subdomains {s} rotates through unless others are
+// given.
+var defaultSubdomains = []string{"a", "b", "c"}
+
+// --------------------------
+// struct URLTemplate
+// --------------------------
+
+// URLTemplate builds tile request URLs from a provider's template, such as
+// "https://{s}.tile.example.com/{z}/{x}/{y}.png". The placeholders are:
+//
+//	{z}, {x}, {y}  XYZ tile coordinates
+//	{-y}           the TMS row, counted from the south
+//	{quadkey}      the key itself, as Bing Maps URLs use
+//	{s}            a subdomain
+//
+// The subdomain is picked from x and y, so a tile always maps to the same
+// host and caches stay warm while requests still spread across hosts. Other
+// text, including unknown placeholders, is copied as is.
+//
+// The zero URLTemplate has an empty template and the default subdomains.
+type URLTemplate struct {
+	template   string
+	subdomains []string
+}
+
+// NewURLTemplate returns a template that rotates {s} through subdomains,
+// which default to "a", "b" and "c". The slice is copied, so later changes
+// to it do not affect the template.
+func NewURLTemplate(template string, subdomains ...string) URLTemplate {
+	return URLTemplate{template: template, subdomains: slices.Clone(subdomains)}
+}
+
+// Expand returns the URL of the key's tile. An error is returned if the key
+// is invalid.
+func (t URLTemplate) Expand(key QuadKey) (string, error) {
+	if err := key.Valid(); err != nil {
+		return "", err
+	}
+	x, y, z := key.XYZ()
+	subdomains := t.subdomains
+	if len(subdomains) == 0 {
+		subdomains = defaultSubdomains
+	}
+	r := strings.NewReplacer(
+		"{z}", strconv.Itoa(z),
+		"{x}", strconv.Itoa(x),
+		"{y}", strconv.Itoa(y),
+		"{-y}", strconv.Itoa(flipY(y, z)),
+		"{quadkey}", string(key),
+		"{s}", subdomains[(x+y)%len(subdomains)],
+	)
+	return r.Replace(t.template), nil
+}
+
+// String returns the template text.
+func (t URLTemplate) String() string {
+	return t.template
+}
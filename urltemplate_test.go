@@ -0,0 +1,64 @@
+package quadkey
+
+import "testing"
+
+func TestURLTemplateExpand(t *testing.T) {
+	key := FromXYZ(3639, 1612, 12)
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"https://tile.example.com/{z}/{x}/{y}.png", "https://tile.example.com/12/3639/1612.png"},
+		{"https://tile.example.com/{z}/{x}/{-y}.png", "https://tile.example.com/12/3639/2483.png"},
+		{"https://t.example.com/tiles/{quadkey}.jpeg", "https://t.example.com/tiles/" + string(key) + ".jpeg"},
+		{"https://{s}.tile.example.com/{z}/{x}/{y}", "https://b.tile.example.com/12/3639/1612"},
+		{"/{z}/{x}/{y}{r}.png", "/12/3639/1612{r}.png"},
+	}
+	for _, tt := range tests {
+		got, err := NewURLTemplate(tt.tmpl).Expand(key)
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: got %q, err %v, want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+}
+
+func TestURLTemplateSubdomains(t *testing.T) {
+	tmpl := NewURLTemplate("{s}", "t0", "t1", "t2", "t3")
+	seen := map[string]int{}
+	for key := range QuadKey("1").DescendantsAtZoom(4) {
+		s, err := tmpl.Expand(key)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", key, err)
+		}
+		again, _ := tmpl.Expand(key)
+		if again != s {
+			t.Fatalf("%q: subdomain changed from %q to %q", key, s, again)
+		}
+		seen[s]++
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected all four subdomains in use, got %v", seen)
+	}
+}
+
+func TestURLTemplateZeroValue(t *testing.T) {
+	if got, err := (URLTemplate{}).Expand("0"); err != nil || got != "" {
+		t.Fatalf("zero template: got %q, %v", got, err)
+	}
+	if got, _ := NewURLTemplate("{s}").Expand("1"); got != "b" {
+		t.Fatalf("default subdomains: got %q, want b", got)
+	}
+
+	subdomains := []string{"t0", "t1"}
+	tmpl := NewURLTemplate("{s}", subdomains...)
+	subdomains[0], subdomains[1] = "x", "x"
+	if got, _ := tmpl.Expand("0"); got != "t0" {
+		t.Fatalf("template shares the caller's slice: got %q", got)
+	}
+}
+
+func TestURLTemplateInvalidKey(t *testing.T) {
+	if _, err := NewURLTemplate("{quadkey}").Expand("01a"); err == nil {
+		t.Fatalf("expected error for an invalid key")
+	}
+}
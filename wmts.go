@@ -0,0 +1,97 @@
+package quadkey
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// --------------------------
+// WMTS addressing
+// --------------------------
+
+// WMTSTileMatrixSet is the identifier of the well-known WMTS tile matrix set
+// whose tiles are the XYZ tiles: TileMatrix is the zoom, TileRow the y and
+// TileCol the x coordinate.
+const WMTSTileMatrixSet = "GoogleMapsCompatible"
+
+// WMTS returns the key's WMTS address in the GoogleMapsCompatible tile
+// matrix set. Invalid keys return -1, -1, -1.
+func (key QuadKey) WMTS() (tileMatrix, tileRow, tileCol int) {
+	x, y, z := key.XYZ()
+	return z, y, x
+}
+
+// FromWMTS returns the key of the tile at a GoogleMapsCompatible WMTS
+// address. An error is returned if the matrix is out of range or the tile
+// lies outside it.
+func FromWMTS(tileMatrix, tileRow, tileCol int) (QuadKey, error) {
+	if tileMatrix < 1 || tileMatrix > MaxZoom {
+		return "", fmt.Errorf("zoom %d is out of range [1, %d]", tileMatrix, MaxZoom)
+	}
+	if tileRow < 0 || tileCol < 0 || int64(tileRow) >= 1<<tileMatrix || int64(tileCol) >= 1<<tileMatrix {
+		return "", fmt.Errorf("tile row %d, col %d is outside tile matrix %d", tileRow, tileCol, tileMatrix)
+	}
+	return FromXYZ(tileCol, tileRow, tileMatrix), nil
+}
+
+// WMTSValues returns the tile parameters of a KVP GetTile request for the
+// key, to be merged with the service's own SERVICE, REQUEST, LAYER and
+// FORMAT parameters. Invalid keys return nil.
+func (key QuadKey) WMTSValues() url.Values {
+	if key.Valid() != nil {
+		return nil
+	}
+	matrix, row, col := key.WMTS()
+	return url.Values{
+		"TileMatrixSet": {WMTSTileMatrixSet},
+		"TileMatrix":    {strconv.Itoa(matrix)},
+		"TileRow":       {strconv.Itoa(row)},
+		"TileCol":       {strconv.Itoa(col)},
+	}
+}
+
+// ParseWMTSValues returns the key addressed by the TileMatrix, TileRow and
+// TileCol parameters of a KVP GetTile request. Parameter names match case
+// insensitively, as WMTS requires, and a prefixed matrix identifier such as
+// "EPSG:3857:12" is read by its last component. An error is returned if a
+// parameter is missing or malformed, or if TileMatrixSet is present and is
+// not GoogleMapsCompatible.
+func ParseWMTSValues(v url.Values) (QuadKey, error) {
+	if set, ok := wmtsParam(v, "TileMatrixSet"); ok && !strings.EqualFold(set, WMTSTileMatrixSet) {
+		return "", fmt.Errorf("tile matrix set %q is not %s", set, WMTSTileMatrixSet)
+	}
+
+	var nums [3]int
+	for i, name := range [3]string{"TileMatrix", "TileRow", "TileCol"} {
+		s, ok := wmtsParam(v, name)
+		if !ok {
+			return "", fmt.Errorf("missing %s parameter", name)
+		}
+		if i == 0 {
+			s = s[strings.LastIndexByte(s, ':')+1:]
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q", name, s)
+		}
+		nums[i] = n
+	}
+	return FromWMTS(nums[0], nums[1], nums[2])
+}
+
+// --------------------------
+// internal function's
+// --------------------------
+
+// wmtsParam returns the first value of the parameter named name, compared
+// case insensitively.
+func wmtsParam(v url.Values, name string) (string, bool) {
+	for k, vals := range v {
+		if strings.EqualFold(k, name) && len(vals) > 0 {
+			return vals[0], true
+		}
+	}
+	return "", false
+}
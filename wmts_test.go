@@ -0,0 +1,62 @@
+package quadkey
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestWMTS(t *testing.T) {
+	key := FromXYZ(3639, 1612, 12)
+	matrix, row, col := key.WMTS()
+	if matrix != 12 || row != 1612 || col != 3639 {
+		t.Fatalf("got (%d, %d, %d), want (12, 1612, 3639)", matrix, row, col)
+	}
+	got, err := FromWMTS(matrix, row, col)
+	if err != nil || got != key {
+		t.Fatalf("FromWMTS: got %q, err %v, want %q", got, err, key)
+	}
+
+	if m, r, c := QuadKey("01a").WMTS(); m != -1 || r != -1 || c != -1 {
+		t.Fatalf("expected (-1,-1,-1) for invalid key, got (%d,%d,%d)", m, r, c)
+	}
+	for _, c := range [][3]int{{0, 0, 0}, {MaxZoom + 1, 0, 0}, {3, 8, 0}, {3, 0, -1}} {
+		if _, err := FromWMTS(c[0], c[1], c[2]); err == nil {
+			t.Fatalf("FromWMTS%v: expected error", c)
+		}
+	}
+}
+
+func TestWMTSValues(t *testing.T) {
+	key := FromXYZ(3639, 1612, 12)
+	v := key.WMTSValues()
+	if v.Get("TileMatrixSet") != WMTSTileMatrixSet || v.Get("TileMatrix") != "12" || v.Get("TileRow") != "1612" || v.Get("TileCol") != "3639" {
+		t.Fatalf("unexpected values %v", v)
+	}
+	if got, err := ParseWMTSValues(v); err != nil || got != key {
+		t.Fatalf("round trip: got %q, err %v", got, err)
+	}
+
+	q, _ := url.ParseQuery("SERVICE=WMTS&REQUEST=GetTile&tilematrixset=googlemapscompatible&TILEMATRIX=EPSG:3857:12&tilerow=1612&TileCol=3639")
+	if got, err := ParseWMTSValues(q); err != nil || got != key {
+		t.Fatalf("KVP request: got %q, err %v", got, err)
+	}
+
+	if QuadKey("01a").WMTSValues() != nil {
+		t.Fatalf("expected nil values for an invalid key")
+	}
+}
+
+func TestParseWMTSValuesInvalid(t *testing.T) {
+	for _, s := range []string{
+		"TileRow=1&TileCol=1",
+		"TileMatrix=2&TileCol=1",
+		"TileMatrix=2&TileRow=x&TileCol=1",
+		"TileMatrix=2&TileRow=1&TileCol=4",
+		"TileMatrixSet=WorldCRS84Quad&TileMatrix=2&TileRow=1&TileCol=1",
+	} {
+		q, _ := url.ParseQuery(s)
+		if got, err := ParseWMTSValues(q); err == nil {
+			t.Fatalf("%q: expected error, got %q", s, got)
+		}
+	}
+}